@@ -0,0 +1,259 @@
+// Package async provides a zerolog.LevelWriter that never blocks the caller.
+// Events are queued and written in batches by a background goroutine, so that
+// request handlers are not held up by slow or unavailable network sinks.
+package async
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultQueueSize is the default number of events that may be waiting to be written.
+	DefaultQueueSize = 1024
+	// DefaultBatchSize is the default maximum number of events in a batch.
+	DefaultBatchSize = 100
+	// DefaultFlushInterval is the default maximum time that an event waits in a partial batch.
+	DefaultFlushInterval = time.Second
+)
+
+// ErrClosed is returned when writing to a closed Writer.
+var ErrClosed = errors.New("async: writer is closed")
+
+// Entry is one log event. Data is a private copy of the bytes written by zerolog.
+type Entry struct {
+	Level zerolog.Level
+	Data  []byte
+}
+
+// Sink receives batches of events from a Writer. WriteBatch is only ever called
+// from a single goroutine.
+type Sink interface {
+	WriteBatch(batch []Entry) error
+}
+
+// SinkFunc adapts a function to the Sink interface.
+type SinkFunc func(batch []Entry) error
+
+// WriteBatch satisfies Sink.
+func (f SinkFunc) WriteBatch(batch []Entry) error {
+	return f(batch)
+}
+
+// WriterSink adapts an ordinary writer to the Sink interface; each event is
+// written separately. If w is a zerolog.LevelWriter, the level is passed on.
+func WriterSink(w io.Writer) Sink {
+	return SinkFunc(func(batch []Entry) error {
+		lw, isLW := w.(zerolog.LevelWriter)
+		for _, e := range batch {
+			var err error
+			if isLW {
+				_, err = lw.WriteLevel(e.Level, e.Data)
+			} else {
+				_, err = w.Write(e.Data)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Options configures a Writer. The zero value is usable.
+type Options struct {
+	// QueueSize limits the number of waiting events; when the queue is full,
+	// new events are dropped. Default DefaultQueueSize.
+	QueueSize int
+	// BatchSize is the maximum number of events passed to the sink at once.
+	// Default DefaultBatchSize.
+	BatchSize int
+	// BatchBytes, if positive, also limits the total size of a batch: the batch is
+	// sent as soon as its events reach this many bytes.
+	BatchBytes int
+	// FlushInterval is the longest time that an event waits for its batch to fill.
+	// Default DefaultFlushInterval.
+	FlushInterval time.Duration
+	// OnError is called (on the background goroutine) when the sink returns an error.
+	// By default, errors are discarded.
+	OnError func(error)
+}
+
+// Writer queues events and writes them to a Sink in the background.
+type Writer struct {
+	sink    Sink
+	opts    Options
+	queue   chan Entry
+	flush   chan chan error
+	done    chan struct{}
+	dropped uint64
+	once    sync.Once
+	mu      sync.RWMutex
+	closed  bool
+}
+
+var _ zerolog.LevelWriter = &Writer{}
+
+// New starts a Writer that sends batches to sink.
+func New(sink Sink, opts Options) *Writer {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultFlushInterval
+	}
+
+	w := &Writer{
+		sink:  sink,
+		opts:  opts,
+		queue: make(chan Entry, opts.QueueSize),
+		flush: make(chan chan error),
+		done:  make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write satisfies io.Writer. The event is dropped if the queue is full.
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel satisfies zerolog.LevelWriter. The event is dropped if the queue is full.
+func (w *Writer) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	// zerolog reuses its buffers, so a copy is needed
+	data := make([]byte, len(p))
+	copy(data, p)
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return 0, ErrClosed
+	}
+
+	select {
+	case w.queue <- Entry{Level: level, Data: data}:
+	default:
+		atomic.AddUint64(&w.dropped, 1)
+	}
+	return len(p), nil
+}
+
+// Dropped returns the number of events that have been dropped because the queue was full.
+func (w *Writer) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// Flush blocks until all the events queued so far have been passed to the sink.
+// It returns the sink's error, if any, for the final batch.
+func (w *Writer) Flush() error {
+	reply := make(chan error)
+	select {
+	case w.flush <- reply:
+		return <-reply
+	case <-w.done:
+		return nil
+	}
+}
+
+// Close flushes any queued events and stops the background goroutine. If the
+// sink is an io.Closer, it is also closed.
+func (w *Writer) Close() error {
+	err := w.Flush()
+
+	w.once.Do(func() {
+		w.mu.Lock()
+		w.closed = true
+		close(w.queue)
+		w.mu.Unlock()
+		<-w.done
+
+		if c, ok := w.sink.(io.Closer); ok {
+			if e := c.Close(); err == nil {
+				err = e
+			}
+		}
+	})
+
+	return err
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Entry, 0, w.opts.BatchSize)
+	size := 0
+
+	full := func() bool {
+		return len(batch) >= w.opts.BatchSize || (w.opts.BatchBytes > 0 && size >= w.opts.BatchBytes)
+	}
+
+	add := func(e Entry) {
+		batch = append(batch, e)
+		size += len(e.Data)
+	}
+
+	send := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := w.sink.WriteBatch(batch)
+		if err != nil && w.opts.OnError != nil {
+			w.opts.OnError(err)
+		}
+		batch = make([]Entry, 0, w.opts.BatchSize)
+		size = 0
+		return err
+	}
+
+	for {
+		select {
+		case e, ok := <-w.queue:
+			if !ok {
+				send()
+				return
+			}
+			add(e)
+			if full() {
+				send()
+			}
+
+		case <-ticker.C:
+			send()
+
+		case reply := <-w.flush:
+			var err error
+		drain:
+			for {
+				select {
+				case e, ok := <-w.queue:
+					if !ok {
+						break drain
+					}
+					add(e)
+					if full() {
+						err = send()
+					}
+				default:
+					break drain
+				}
+			}
+			if e := send(); e != nil {
+				err = e
+			}
+			reply <- err
+		}
+	}
+}
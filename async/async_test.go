@@ -0,0 +1,119 @@
+package async
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]Entry
+	block   chan struct{}
+}
+
+func (r *recorder) WriteBatch(batch []Entry) error {
+	if r.block != nil {
+		<-r.block
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, batch)
+	return nil
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, b := range r.batches {
+		n += len(b)
+	}
+	return n
+}
+
+func TestBatchSize(t *testing.T) {
+	g := NewGomegaWithT(t)
+	r := &recorder{}
+	w := New(r, Options{BatchSize: 2, FlushInterval: time.Hour})
+
+	z := zerolog.New(w)
+	z.Info().Msg("a")
+	z.Warn().Msg("b")
+	z.Error().Msg("c")
+
+	g.Eventually(r.count).Should(Equal(2))
+	g.Expect(w.Flush()).To(Succeed())
+	g.Expect(r.batches).To(HaveLen(2))
+	g.Expect(r.batches[0][0].Level).To(Equal(zerolog.InfoLevel))
+	g.Expect(string(r.batches[0][1].Data)).To(Equal(`{"level":"warn","message":"b"}` + "\n"))
+	g.Expect(r.batches[1]).To(HaveLen(1))
+
+	g.Expect(w.Close()).To(Succeed())
+	_, err := w.Write([]byte("x"))
+	g.Expect(err).To(Equal(ErrClosed))
+}
+
+func TestFlushInterval(t *testing.T) {
+	g := NewGomegaWithT(t)
+	r := &recorder{}
+	w := New(r, Options{FlushInterval: 10 * time.Millisecond})
+	defer w.Close()
+
+	w.Write([]byte("a"))
+
+	g.Eventually(r.count).Should(Equal(1))
+}
+
+func TestDropWhenFull(t *testing.T) {
+	g := NewGomegaWithT(t)
+	r := &recorder{block: make(chan struct{})}
+	w := New(r, Options{QueueSize: 1, BatchSize: 1})
+
+	for i := 0; i < 10; i++ {
+		_, err := w.Write([]byte("a"))
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	g.Expect(w.Dropped()).To(BeNumerically(">=", 8))
+	close(r.block)
+	g.Expect(w.Close()).To(Succeed())
+}
+
+func TestWriterSink_and_OnError(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &bytes.Buffer{}
+	w := New(WriterSink(buf), Options{})
+	w.Write([]byte("a"))
+	w.Write([]byte("b"))
+	g.Expect(w.Close()).To(Succeed())
+	g.Expect(buf.String()).To(Equal("ab"))
+
+	e1 := errors.New("x")
+	var got error
+	w = New(SinkFunc(func([]Entry) error { return e1 }), Options{OnError: func(err error) { got = err }})
+	w.Write([]byte("a"))
+	g.Expect(w.Flush()).To(Equal(e1))
+	g.Expect(got).To(Equal(e1))
+	w.Close()
+}
+
+func TestBatchBytes(t *testing.T) {
+	g := NewGomegaWithT(t)
+	r := &recorder{}
+	w := New(r, Options{BatchBytes: 5, FlushInterval: time.Hour})
+
+	w.Write([]byte("abc"))
+	w.Write([]byte("def"))
+	w.Write([]byte("g"))
+
+	g.Eventually(r.count).Should(Equal(2))
+	g.Expect(w.Close()).To(Succeed())
+	g.Expect(r.batches).To(HaveLen(2))
+	g.Expect(r.batches[1]).To(HaveLen(1))
+}
@@ -0,0 +1,270 @@
+// Package bulk provides a batching HTTP writer that ships log events to Loki's
+// push API or to an Elasticsearch _bulk endpoint.
+//
+// Events are queued by an async.Writer and sent in batches, which are flushed
+// when they reach a given number of events or bytes, or after a time interval.
+// Failed requests are retried with jittered exponential backoff; batches that
+// still cannot be delivered can be spilled to disk and are re-sent once the
+// endpoint recovers.
+//
+//	w := bulk.New("http://loki:3100/loki/api/v1/push", bulk.Loki{}, bulk.Options{Gzip: true})
+//	defer w.Close()
+//	logger.SetOutput(w)
+package bulk
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rickb777/ech0/v3/async"
+)
+
+// Format encodes a batch of events as the body of one HTTP request.
+type Format interface {
+	// Encode returns the request body and its content type.
+	Encode(batch []async.Entry) (body []byte, contentType string, err error)
+	// Check inspects a successful response body, returning an error if the
+	// endpoint reported that some events were rejected.
+	Check(body []byte) error
+}
+
+// Options configures a Shipper. The zero value is usable.
+type Options struct {
+	// Client sends the requests; default http.DefaultClient.
+	Client *http.Client
+	// Header is added to every request, e.g. for authorisation.
+	Header http.Header
+	// Gzip compresses request bodies.
+	Gzip bool
+	// MaxRetries is the number of times a failed request is retried; default 3.
+	MaxRetries int
+	// MinBackoff is the initial delay before a retry; default 100ms.
+	MinBackoff time.Duration
+	// MaxBackoff limits the delay between successive retries; default 10s.
+	MaxBackoff time.Duration
+	// SpillDir, if set, is a directory in which undeliverable batches are kept.
+	// They are re-sent, oldest first, after the next successful request.
+	SpillDir string
+	// MaxSpillFiles limits the number of spilled batches; the oldest are discarded
+	// first. Zero means no limit.
+	MaxSpillFiles int
+	// Async configures the queueing and batching of events.
+	Async async.Options
+}
+
+// Shipper is an async.Sink that posts batches of events to an HTTP endpoint.
+// It is normally used via New, which wraps it in an async.Writer.
+type Shipper struct {
+	url    string
+	format Format
+	opts   Options
+	mu     sync.Mutex
+	seq    uint64
+	sleep  func(time.Duration)
+}
+
+var _ async.Sink = &Shipper{}
+
+// New creates a writer that posts batches of events to url using the given format.
+func New(url string, format Format, opts Options) *async.Writer {
+	return async.New(NewShipper(url, format, opts), opts.Async)
+}
+
+// NewShipper creates a synchronous Shipper. Most users will prefer New.
+func NewShipper(url string, format Format, opts Options) *Shipper {
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.MaxRetries <= 0 {
+		opts.MaxRetries = 3
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = 100 * time.Millisecond
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = 10 * time.Second
+	}
+
+	return &Shipper{url: url, format: format, opts: opts, sleep: time.Sleep}
+}
+
+// WriteBatch satisfies async.Sink. If the batch cannot be delivered and a spill
+// directory is configured, it is saved there and no error is returned.
+func (s *Shipper) WriteBatch(batch []async.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unavailable, err := s.post(batch)
+	if err != nil {
+		if !unavailable || s.opts.SpillDir == "" {
+			return err
+		}
+		return s.spill(batch)
+	}
+
+	return s.replay()
+}
+
+// post sends one batch, retrying when the failure might be temporary. If the
+// retries are exhausted, unavailable is true.
+func (s *Shipper) post(batch []async.Entry) (unavailable bool, err error) {
+	body, contentType, err := s.format.Encode(batch)
+	if err != nil {
+		return false, err
+	}
+
+	if s.opts.Gzip {
+		if body, err = compress(body); err != nil {
+			return false, err
+		}
+	}
+
+	backoff := s.opts.MinBackoff
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		retry, err = s.attempt(body, contentType)
+		if err == nil || !retry || attempt >= s.opts.MaxRetries {
+			return retry, err
+		}
+
+		// "equal jitter": wait between half and all of the backoff interval
+		s.sleep(backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1)))
+		backoff *= 2
+		if backoff > s.opts.MaxBackoff {
+			backoff = s.opts.MaxBackoff
+		}
+	}
+}
+
+func (s *Shipper) attempt(body []byte, contentType string) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	for k, v := range s.opts.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", contentType)
+	if s.opts.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	res, err := s.opts.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer res.Body.Close()
+
+	respBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return true, err
+	}
+
+	if res.StatusCode >= 300 {
+		err = fmt.Errorf("bulk: %s %s", res.Status, strings.TrimSpace(string(respBody)))
+		return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500, err
+	}
+
+	return false, s.format.Check(respBody)
+}
+
+//-------------------------------------------------------------------------------------------------
+
+const spillExt = ".ndjson"
+
+// spill saves a batch as newline-delimited JSON events.
+func (s *Shipper) spill(batch []async.Entry) error {
+	if err := os.MkdirAll(s.opts.SpillDir, 0o755); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, e := range batch {
+		buf.Write(bytes.TrimRight(e.Data, "\n"))
+		buf.WriteByte('\n')
+	}
+
+	name := fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), atomic.AddUint64(&s.seq, 1), spillExt)
+	if err := ioutil.WriteFile(filepath.Join(s.opts.SpillDir, name), buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+
+	if s.opts.MaxSpillFiles > 0 {
+		files, err := s.spilled()
+		if err != nil {
+			return err
+		}
+		for len(files) > s.opts.MaxSpillFiles {
+			os.Remove(files[0])
+			files = files[1:]
+		}
+	}
+	return nil
+}
+
+// replay re-sends spilled batches, oldest first, stopping at the first failure.
+func (s *Shipper) replay() error {
+	if s.opts.SpillDir == "" {
+		return nil
+	}
+
+	files, err := s.spilled()
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			return err
+		}
+
+		var batch []async.Entry
+		for _, line := range bytes.Split(data, []byte{'\n'}) {
+			if len(line) > 0 {
+				batch = append(batch, async.Entry{Data: line})
+			}
+		}
+
+		// a batch that was rejected is not kept, but if the endpoint is
+		// still unavailable, the file remains for later
+		if unavailable, _ := s.post(batch); unavailable {
+			return nil
+		}
+		if err = os.Remove(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spilled lists the spill files, oldest first.
+func (s *Shipper) spilled() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(s.opts.SpillDir, "*"+spillExt))
+	sort.Strings(files)
+	return files, err
+}
+
+func compress(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,175 @@
+package bulk
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gommon "github.com/labstack/gommon/log"
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/ech0/v3"
+	"github.com/rickb777/ech0/v3/async"
+	"github.com/rs/zerolog"
+)
+
+type request struct {
+	header http.Header
+	body   string
+}
+
+// stub is a stand-in endpoint that replies with the queued statuses
+// (then 200 OK) and records every request.
+type stub struct {
+	mu       sync.Mutex
+	statuses []int
+	reply    string
+	requests []request
+}
+
+func (s *stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var body []byte
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, _ := gzip.NewReader(r.Body)
+		body, _ = ioutil.ReadAll(zr)
+	} else {
+		body, _ = ioutil.ReadAll(r.Body)
+	}
+	s.requests = append(s.requests, request{header: r.Header, body: string(body)})
+
+	if len(s.statuses) > 0 {
+		w.WriteHeader(s.statuses[0])
+		s.statuses = s.statuses[1:]
+		return
+	}
+	w.Write([]byte(s.reply))
+}
+
+func TestLoki_gzip(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s := &stub{}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	w := New(srv.URL, Loki{Labels: map[string]string{"app": "a1"}}, Options{Gzip: true, Async: async.Options{FlushInterval: time.Hour}})
+	l := ech0.New(w, "svc")
+	l.SetLevel(gommon.INFO)
+	l.Info("m1")
+	l.Warn("m2")
+	l.Info("m3")
+	g.Expect(w.Close()).To(Succeed())
+
+	g.Expect(s.requests).To(HaveLen(1))
+	g.Expect(s.requests[0].header.Get("Content-Type")).To(Equal("application/json"))
+
+	var push struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	g.Expect(json.Unmarshal([]byte(s.requests[0].body), &push)).To(Succeed())
+	g.Expect(push.Streams).To(HaveLen(2))
+	g.Expect(push.Streams[0].Stream).To(Equal(map[string]string{"app": "a1", "level": "info", "prefix": "svc"}))
+	g.Expect(push.Streams[0].Values).To(HaveLen(2))
+	g.Expect(push.Streams[0].Values[1][1]).To(ContainSubstring(`"message":"m3"`))
+	g.Expect(push.Streams[1].Stream["level"]).To(Equal("warn"))
+}
+
+func TestElasticsearch_retry(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s := &stub{statuses: []int{503, 429}, reply: `{"errors":false}`}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	sh := NewShipper(srv.URL+"/_bulk", Elasticsearch{Index: "logs"}, Options{Header: http.Header{"Authorization": {"ApiKey k"}}})
+	var delays []time.Duration
+	sh.sleep = func(d time.Duration) { delays = append(delays, d) }
+
+	err := sh.WriteBatch(entries(`{"level":"info","message":"m1"}`+"\n", `{"level":"error","message":"m2"}`+"\n"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(delays).To(HaveLen(2))
+	g.Expect(delays[0]).To(BeNumerically("~", 75*time.Millisecond, 25*time.Millisecond))
+	g.Expect(delays[1]).To(BeNumerically("~", 150*time.Millisecond, 50*time.Millisecond))
+
+	g.Expect(s.requests).To(HaveLen(3))
+	g.Expect(s.requests[2].header.Get("Content-Type")).To(Equal("application/x-ndjson"))
+	g.Expect(s.requests[2].header.Get("Authorization")).To(Equal("ApiKey k"))
+	g.Expect(s.requests[2].body).To(Equal(`{"index":{"_index":"logs"}}
+{"level":"info","message":"m1"}
+{"index":{"_index":"logs"}}
+{"level":"error","message":"m2"}
+`))
+}
+
+func TestElasticsearch_rejected(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s := &stub{reply: `{"errors":true,"items":[{"create":{"status":201}},{"create":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"bad"}}}]}`}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	sh := NewShipper(srv.URL, Elasticsearch{Create: true}, Options{SpillDir: t.TempDir()})
+
+	err := sh.WriteBatch(entries(`{"message":"m1"}`, `{"message":"m2"}`))
+	g.Expect(err).To(MatchError("bulk: 1 of 2 documents rejected; first: mapper_parsing_exception: bad"))
+	g.Expect(s.requests[0].body).To(HavePrefix(`{"create":{}}`))
+}
+
+func TestClientError_is_not_retried(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s := &stub{statuses: []int{400}}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	sh := NewShipper(srv.URL, Loki{}, Options{})
+	sh.sleep = func(time.Duration) { t.Fatal("unexpected retry") }
+
+	err := sh.WriteBatch(entries(`{"message":"m1"}`))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(s.requests).To(HaveLen(1))
+}
+
+func TestSpill_and_replay(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s := &stub{statuses: []int{500, 500, 500, 500}}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	sh := NewShipper(srv.URL, Elasticsearch{}, Options{MaxRetries: 1, SpillDir: dir, MaxSpillFiles: 1})
+	sh.sleep = func(time.Duration) {}
+
+	g.Expect(sh.WriteBatch(entries(`{"message":"m1"}`))).To(Succeed())
+	g.Expect(sh.WriteBatch(entries(`{"message":"m2"}`))).To(Succeed())
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*"))
+	g.Expect(files).To(HaveLen(1)) // m1 was discarded
+
+	// the endpoint has recovered
+	g.Expect(sh.WriteBatch(entries(`{"message":"m3"}`))).To(Succeed())
+
+	files, _ = filepath.Glob(filepath.Join(dir, "*"))
+	g.Expect(files).To(BeEmpty())
+	g.Expect(s.requests).To(HaveLen(6))
+	g.Expect(s.requests[4].body).To(ContainSubstring("m3"))
+	g.Expect(s.requests[5].body).To(ContainSubstring("m2"))
+	g.Expect(strings.Count(s.requests[5].body, "\n")).To(Equal(2))
+}
+
+func entries(lines ...string) []async.Entry {
+	batch := make([]async.Entry, len(lines))
+	for i, l := range lines {
+		batch[i] = async.Entry{Level: zerolog.NoLevel, Data: []byte(l)}
+	}
+	return batch
+}
@@ -0,0 +1,78 @@
+package bulk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rickb777/ech0/v3/async"
+)
+
+// Elasticsearch formats batches for the _bulk API. Every event is indexed
+// as a separate document.
+type Elasticsearch struct {
+	// Index names the target index or data stream. If blank, the index must be
+	// given in the endpoint URL, e.g. http://es:9200/logs/_bulk.
+	Index string
+	// Create uses the "create" action instead of "index"; this is required for data streams.
+	Create bool
+}
+
+// Encode satisfies Format.
+func (f Elasticsearch) Encode(batch []async.Entry) ([]byte, string, error) {
+	action := "index"
+	if f.Create {
+		action = "create"
+	}
+
+	meta := map[string]interface{}{}
+	if f.Index != "" {
+		meta["_index"] = f.Index
+	}
+	header, err := json.Marshal(map[string]interface{}{action: meta})
+	if err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	for _, e := range batch {
+		buf.Write(header)
+		buf.WriteByte('\n')
+		buf.Write(bytes.TrimRight(e.Data, "\n"))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), "application/x-ndjson", nil
+}
+
+// Check satisfies Format. Elasticsearch returns 200 OK even when some documents
+// were rejected, so the response body has to be inspected.
+func (f Elasticsearch) Check(body []byte) error {
+	var res struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+			Error  struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+
+	if err := json.Unmarshal(body, &res); err != nil || !res.Errors {
+		return nil
+	}
+
+	failed := 0
+	var first string
+	for _, item := range res.Items {
+		for _, r := range item {
+			if r.Status >= 300 {
+				if failed == 0 {
+					first = r.Error.Type + ": " + r.Error.Reason
+				}
+				failed++
+			}
+		}
+	}
+	return fmt.Errorf("bulk: %d of %d documents rejected; first: %s", failed, len(res.Items), first)
+}
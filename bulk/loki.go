@@ -0,0 +1,94 @@
+package bulk
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rickb777/ech0/v3/async"
+	"github.com/rickb777/ech0/v3/internal/event"
+	"github.com/rs/zerolog"
+)
+
+// Loki formats batches for Loki's push API (/loki/api/v1/push). Each event
+// becomes one log line; its stream labels are the level and the prefix (if any),
+// together with the static Labels.
+type Loki struct {
+	// Labels are added to every stream, e.g. {"app": "orders"}.
+	Labels map[string]string
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// Encode satisfies Format.
+func (f Loki) Encode(batch []async.Entry) ([]byte, string, error) {
+	var streams []*lokiStream
+	index := make(map[string]*lokiStream)
+
+	for _, e := range batch {
+		line := bytes.TrimRight(e.Data, "\n")
+		fields, err := event.Decode(line)
+		if err != nil {
+			continue // not JSON; ignore it
+		}
+
+		labels := f.labels(e.Level, fields)
+		key := labelKey(labels)
+		stream, exists := index[key]
+		if !exists {
+			stream = &lokiStream{Stream: labels}
+			index[key] = stream
+			streams = append(streams, stream)
+		}
+
+		t, ok := event.Time(fields)
+		if !ok {
+			t = time.Now()
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(t.UnixNano(), 10), string(line)})
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"streams": streams})
+	return body, "application/json", err
+}
+
+// Check satisfies Format. Loki reports failures only via the status code.
+func (f Loki) Check([]byte) error {
+	return nil
+}
+
+func (f Loki) labels(level zerolog.Level, fields map[string]interface{}) map[string]string {
+	labels := make(map[string]string, len(f.Labels)+2)
+	for k, v := range f.Labels {
+		labels[k] = v
+	}
+
+	if level == zerolog.NoLevel {
+		level = event.Level(fields)
+	}
+	if level == zerolog.NoLevel {
+		labels["level"] = "none"
+	} else {
+		labels["level"] = level.String()
+	}
+
+	if p, ok := fields["prefix"].(string); ok && p != "" {
+		labels["prefix"] = p
+	}
+	return labels
+}
+
+func labelKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k, v := range labels {
+		keys = append(keys, k+"="+v)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
@@ -0,0 +1,21 @@
+package ech0
+
+import "context"
+
+type ctxKey struct{}
+
+// WithContext returns a copy of ctx that holds z. Use Ctx to retrieve it.
+func WithContext(ctx context.Context, z Zero) context.Context {
+	return context.WithValue(ctx, ctxKey{}, z)
+}
+
+// Ctx returns the logger held by ctx (see WithContext). If there is none, Nop is returned,
+// so the result can always be used safely.
+func Ctx(ctx context.Context) Zero {
+	if ctx != nil {
+		if z, ok := ctx.Value(ctxKey{}).(Zero); ok && z != nil {
+			return z
+		}
+	}
+	return nop
+}
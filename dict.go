@@ -0,0 +1,365 @@
+package ech0
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Dict creates a dictionary to be added to an event using the ZeroEvent.Dict method.
+// Call usual field methods like Str, Int etc to add fields to the dictionary.
+//
+// The fields are recorded and are only marshaled when the dictionary is added to an event,
+// so the dictionary can be used with any ZeroEvent implementation. Other implementations
+// can provide dictionaries too, provided they implement ObjectMarshaler.
+func Dict() ZeroEvent {
+	return &dictRecorder{}
+}
+
+// unwrapDict finds a branch of a Tee event that can be used as a dictionary because
+// it can replay its fields. The result is nil if there is none.
+func unwrapDict(dict ZeroEvent) ZeroEvent {
+	te, ok := dict.(*teeEvent)
+	if !ok {
+		return dict
+	}
+
+	for _, e := range te.events {
+		d := unwrapDict(e)
+		if _, ok := d.(ObjectMarshaler); ok {
+			return d
+		}
+	}
+	return nil
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// dictRecorder remembers the fields that are added, in order to replay them later.
+type dictRecorder []func(ZeroEvent)
+
+var (
+	_ ZeroEvent       = &dictRecorder{}
+	_ ObjectMarshaler = &dictRecorder{}
+)
+
+// MarshalZeroObject replays the recorded fields onto e.
+func (d *dictRecorder) MarshalZeroObject(e ZeroEvent) {
+	for _, op := range *d {
+		op(e)
+	}
+}
+
+func (d *dictRecorder) add(op func(ZeroEvent)) ZeroEvent {
+	*d = append(*d, op)
+	return d
+}
+
+// Send does nothing; use ZeroEvent.Dict to add the dictionary to an event.
+func (d *dictRecorder) Send() {}
+
+// Msg does nothing; use ZeroEvent.Dict to add the dictionary to an event.
+func (d *dictRecorder) Msg(s string) {}
+
+// Msgf does nothing; use ZeroEvent.Dict to add the dictionary to an event.
+func (d *dictRecorder) Msgf(format string, v ...interface{}) {}
+
+// Enabled returns true.
+func (d *dictRecorder) Enabled() bool {
+	return true
+}
+
+// Discard removes the recorded fields.
+func (d *dictRecorder) Discard() ZeroEvent {
+	*d = nil
+	return d
+}
+
+// Func runs f immediately.
+func (d *dictRecorder) Func(f func(e ZeroEvent)) ZeroEvent {
+	f(d)
+	return d
+}
+
+// Stack enables stack trace printing for the error passed to Err().
+func (d *dictRecorder) Stack() ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Stack() })
+}
+
+// Caller adds the file:line of the caller with the zerolog.CallerFieldName key.
+// The argument skip is the number of additional stack frames to ascend.
+func (d *dictRecorder) Caller(skip ...int) ZeroEvent {
+	sk := 1 // skip this method
+	if len(skip) > 0 {
+		sk += skip[0]
+	}
+	_, file, line, ok := runtime.Caller(sk)
+	if !ok {
+		return d
+	}
+	caller := zerolog.CallerMarshalFunc(file, line)
+	return d.add(func(target ZeroEvent) { target.Str(zerolog.CallerFieldName, caller) })
+}
+
+// Timestamp adds the current time with the zerolog.TimestampFieldName key.
+// The time is when Timestamp is called, not when the dictionary is marshaled.
+func (d *dictRecorder) Timestamp() ZeroEvent {
+	now := zerolog.TimestampFunc()
+	return d.add(func(target ZeroEvent) { target.Time(zerolog.TimestampFieldName, now) })
+}
+
+// Dict adds the field key with a dict to the dictionary.
+func (d *dictRecorder) Dict(key string, dict ZeroEvent) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Dict(key, dict) })
+}
+
+// AnErr adds the field key with serialized err to the dictionary.
+// If err is nil, no field is added.
+func (d *dictRecorder) AnErr(key string, err error) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.AnErr(key, err) })
+}
+
+// Bool adds the field key with val as a bool to the dictionary.
+func (d *dictRecorder) Bool(key string, val bool) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Bool(key, val) })
+}
+
+// Bools adds the field key with val as a []bool to the dictionary.
+func (d *dictRecorder) Bools(key string, b []bool) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Bools(key, b) })
+}
+
+// Bytes adds the field key with val as a string to the dictionary.
+//
+// Runes outside of normal ASCII ranges will be hex-encoded in the resulting
+// JSON.
+func (d *dictRecorder) Bytes(key string, val []byte) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Bytes(key, val) })
+}
+
+// Object marshals an object that implements the ObjectMarshaler interface.
+func (d *dictRecorder) Object(key string, obj ObjectMarshaler) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Object(key, obj) })
+}
+
+// EmbedObject marshals an object that implements the ObjectMarshaler interface,
+// adding its fields directly to the dictionary.
+func (d *dictRecorder) EmbedObject(obj ObjectMarshaler) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.EmbedObject(obj) })
+}
+
+// Array adds the field key with an array to the dictionary.
+// Use Arr() to create the array or pass a type that
+// implements the ArrayMarshaler interface.
+func (d *dictRecorder) Array(key string, arr ArrayMarshaler) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Array(key, arr) })
+}
+
+// Dur adds the field key with duration d stored as zerolog.DurationFieldUnit.
+// If zerolog.DurationFieldInteger is true, durations are rendered as integer
+// instead of float.
+func (d *dictRecorder) Dur(key string, val time.Duration) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Dur(key, val) })
+}
+
+// Err adds the field "error" with serialized err to the dictionary.
+// If err is nil, no field is added.
+//
+// To customize the key name, change zerolog.ErrorFieldName.
+//
+// If Stack() has been called before, the err is passed to the logger's StackMarshaler
+// (see Zero.ErrorStack) and the result is appended to the zerolog.ErrorStackFieldName.
+// The global zerolog.ErrorStackMarshaler is not used.
+func (d *dictRecorder) Err(err error) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Err(err) })
+}
+
+// Hex adds the field key with val as a hex string to the dictionary.
+func (d *dictRecorder) Hex(key string, val []byte) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Hex(key, val) })
+}
+
+// Int adds the field key with i as a int to the dictionary.
+func (d *dictRecorder) Int(key string, val int) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Int(key, val) })
+}
+
+// Ints adds the field key with i as a int to the dictionary.
+func (d *dictRecorder) Ints(key string, val []int) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Ints(key, val) })
+}
+
+// Int64 adds the field key with i as a int64 to the dictionary.
+func (d *dictRecorder) Int64(key string, val int64) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Int64(key, val) })
+}
+
+// Interface adds the field key with i marshaled using reflection.
+func (d *dictRecorder) Interface(key string, val interface{}) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Interface(key, val) })
+}
+
+// Str adds the field key with val as a string to the dictionary.
+func (d *dictRecorder) Str(key string, val string) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Str(key, val) })
+}
+
+// Strs adds the field key with vals as a []string to the dictionary.
+func (d *dictRecorder) Strs(key string, val []string) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Strs(key, val) })
+}
+
+// Stringer adds the field key with val.String() (or null if val is nil) to the dictionary.
+func (d *dictRecorder) Stringer(key string, val fmt.Stringer) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Stringer(key, val) })
+}
+
+// Time adds the field key with t formated as string using zerolog.TimeFieldFormat.
+func (d *dictRecorder) Time(key string, val time.Time) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Time(key, val) })
+}
+
+// Uint adds the field key with i as a uint to the dictionary.
+func (d *dictRecorder) Uint(key string, val uint) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Uint(key, val) })
+}
+
+// Uint adds the field key with i as a uint to the dictionary.
+func (d *dictRecorder) Uints(key string, val []uint) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Uints(key, val) })
+}
+
+// Uint64 adds the field key with i as a uint to the dictionary.
+func (d *dictRecorder) Uint64(key string, val uint64) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Uint64(key, val) })
+}
+
+// Errs adds the field key with errs as an array of serialized errors to the dictionary.
+func (d *dictRecorder) Errs(key string, errs []error) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Errs(key, errs) })
+}
+
+// Float32 adds the field key with val as a float32 to the dictionary.
+func (d *dictRecorder) Float32(key string, val float32) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Float32(key, val) })
+}
+
+// Floats32 adds the field key with val as a []float32 to the dictionary.
+func (d *dictRecorder) Floats32(key string, val []float32) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Floats32(key, val) })
+}
+
+// Float64 adds the field key with val as a float64 to the dictionary.
+func (d *dictRecorder) Float64(key string, val float64) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Float64(key, val) })
+}
+
+// Floats64 adds the field key with val as a []float64 to the dictionary.
+func (d *dictRecorder) Floats64(key string, val []float64) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Floats64(key, val) })
+}
+
+// Int8 adds the field key with val as a int8 to the dictionary.
+func (d *dictRecorder) Int8(key string, val int8) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Int8(key, val) })
+}
+
+// Int16 adds the field key with val as a int16 to the dictionary.
+func (d *dictRecorder) Int16(key string, val int16) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Int16(key, val) })
+}
+
+// Int32 adds the field key with val as a int32 to the dictionary.
+func (d *dictRecorder) Int32(key string, val int32) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Int32(key, val) })
+}
+
+// Ints8 adds the field key with val as a []int8 to the dictionary.
+func (d *dictRecorder) Ints8(key string, val []int8) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Ints8(key, val) })
+}
+
+// Ints16 adds the field key with val as a []int16 to the dictionary.
+func (d *dictRecorder) Ints16(key string, val []int16) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Ints16(key, val) })
+}
+
+// Ints32 adds the field key with val as a []int32 to the dictionary.
+func (d *dictRecorder) Ints32(key string, val []int32) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Ints32(key, val) })
+}
+
+// Ints64 adds the field key with val as a []int64 to the dictionary.
+func (d *dictRecorder) Ints64(key string, val []int64) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Ints64(key, val) })
+}
+
+// Uint8 adds the field key with val as a uint8 to the dictionary.
+func (d *dictRecorder) Uint8(key string, val uint8) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Uint8(key, val) })
+}
+
+// Uint16 adds the field key with val as a uint16 to the dictionary.
+func (d *dictRecorder) Uint16(key string, val uint16) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Uint16(key, val) })
+}
+
+// Uint32 adds the field key with val as a uint32 to the dictionary.
+func (d *dictRecorder) Uint32(key string, val uint32) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Uint32(key, val) })
+}
+
+// Uints8 adds the field key with val as a []uint8 to the dictionary.
+func (d *dictRecorder) Uints8(key string, val []uint8) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Uints8(key, val) })
+}
+
+// Uints16 adds the field key with val as a []uint16 to the dictionary.
+func (d *dictRecorder) Uints16(key string, val []uint16) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Uints16(key, val) })
+}
+
+// Uints32 adds the field key with val as a []uint32 to the dictionary.
+func (d *dictRecorder) Uints32(key string, val []uint32) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Uints32(key, val) })
+}
+
+// Uints64 adds the field key with val as a []uint64 to the dictionary.
+func (d *dictRecorder) Uints64(key string, val []uint64) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Uints64(key, val) })
+}
+
+// Times adds the field key with val formated as string using zerolog.TimeFieldFormat.
+func (d *dictRecorder) Times(key string, val []time.Time) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Times(key, val) })
+}
+
+// Durs adds the field key with val stored as zerolog.DurationFieldUnit.
+func (d *dictRecorder) Durs(key string, val []time.Duration) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.Durs(key, val) })
+}
+
+// TimeDiff adds the field key with positive duration between time t and start.
+// If time t is not greater than start, duration will be 0.
+// Duration format follows the same principle as Dur().
+func (d *dictRecorder) TimeDiff(key string, t time.Time, start time.Time) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.TimeDiff(key, t, start) })
+}
+
+// IPAddr adds IPv4 or IPv6 Address to the dictionary.
+func (d *dictRecorder) IPAddr(key string, ip net.IP) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.IPAddr(key, ip) })
+}
+
+// IPPrefix adds IPv4 or IPv6 Prefix (address and mask) to the dictionary.
+func (d *dictRecorder) IPPrefix(key string, pfx net.IPNet) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.IPPrefix(key, pfx) })
+}
+
+// MACAddr adds MAC address to the dictionary.
+func (d *dictRecorder) MACAddr(key string, ha net.HardwareAddr) ZeroEvent {
+	return d.add(func(target ZeroEvent) { target.MACAddr(key, ha) })
+}
@@ -0,0 +1,125 @@
+package ech0
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// StartTimeKey is the echo.Context key under which StartTimer records the start of each request.
+const StartTimeKey = "ech0.start"
+
+// loggedKey marks a request whose error has already been logged.
+const loggedKey = "ech0.logged"
+
+// ErrorHandlerOptions configures HTTPErrorHandler. The zero value is usable.
+type ErrorHandlerOptions struct {
+	// ClientErrorLevel is the level at which errors with a status code below 500 are
+	// logged. The zero value is zerolog.DebugLevel. Server errors are always logged
+	// at zerolog.ErrorLevel.
+	ClientErrorLevel zerolog.Level
+	// Handler sends the error response; default echo's DefaultHTTPErrorHandler.
+	Handler echo.HTTPErrorHandler
+	// RequestIDHeader is the header holding the request ID; default echo.HeaderXRequestID.
+	// The response header is used if set (e.g. by the RequestID middleware), otherwise
+	// the request header.
+	RequestIDHeader string
+}
+
+// HTTPErrorHandler returns an echo.HTTPErrorHandler that logs errors as structured events
+// before delegating the response to another handler. Use it in place of echo's default
+// handler, which logs with e.Logger.Error(err), losing all structure.
+//
+//	e.HTTPErrorHandler = ech0.HTTPErrorHandler(zero, ech0.ErrorHandlerOptions{ClientErrorLevel: zerolog.WarnLevel})
+//
+// Each event has the status code, route, method, request ID and the error, along with its
+// chain of wrapped errors (see ZeroEvent.Stack). For an echo.HTTPError, the message is
+// its Message and its Internal error is included. If StartTimer is in use, the latency
+// is included too.
+//
+// Echo may call the error handler more than once for the same request, e.g. after the
+// Logger middleware, so only the first error for each request is logged.
+func HTTPErrorHandler(z Zero, opts ErrorHandlerOptions) echo.HTTPErrorHandler {
+	if opts.RequestIDHeader == "" {
+		opts.RequestIDHeader = echo.HeaderXRequestID
+	}
+
+	return func(err error, c echo.Context) {
+		if c.Get(loggedKey) == nil {
+			c.Set(loggedKey, true)
+			logHTTPError(z, opts, err, c)
+		}
+
+		if opts.Handler != nil {
+			opts.Handler(err, c)
+		} else {
+			c.Echo().DefaultHTTPErrorHandler(err, c)
+		}
+	}
+}
+
+func logHTTPError(z Zero, opts ErrorHandlerOptions, err error, c echo.Context) {
+	code := http.StatusInternalServerError
+	msg := ""
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		code = he.Code
+		if he.Message != nil {
+			msg = fmt.Sprint(he.Message)
+		}
+	}
+
+	if msg == "" {
+		msg = http.StatusText(code)
+	}
+
+	level := opts.ClientErrorLevel
+	if code >= http.StatusInternalServerError {
+		level = zerolog.ErrorLevel
+	}
+
+	ev := z.WithLevel(level)
+	if !ev.Enabled() {
+		ev.Discard()
+		return
+	}
+
+	req := c.Request()
+	ev = ev.Int(StatusFieldName, code).
+		Str("route", c.Path()).
+		Str("method", req.Method)
+
+	rid := c.Response().Header().Get(opts.RequestIDHeader)
+	if rid == "" {
+		rid = req.Header.Get(opts.RequestIDHeader)
+	}
+	if rid != "" {
+		ev = ev.Str("request_id", rid)
+	}
+
+	if he != nil && he.Internal != nil {
+		ev = ev.AnErr(InternalErrorFieldName, he.Internal)
+	}
+
+	if start, ok := c.Get(StartTimeKey).(time.Time); ok {
+		ev = ev.Dur("latency", time.Since(start))
+	}
+
+	ev.Stack().Err(err).Msg(msg)
+}
+
+// StartTimer returns middleware that records the start time of each request, allowing
+// HTTPErrorHandler to log the latency. Add it before other middleware.
+func StartTimer() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(StartTimeKey, time.Now())
+			return next(c)
+		}
+	}
+}
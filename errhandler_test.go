@@ -0,0 +1,84 @@
+package ech0
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+func TestHTTPErrorHandler(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	z := Wrap(zerolog.New(buf)).ErrorStack(nil)
+
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler(z, ErrorHandlerOptions{ClientErrorLevel: zerolog.WarnLevel})
+	e.Use(StartTimer(), middleware.RequestID(), middleware.Logger())
+	e.GET("/users/:id", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusNotFound, "no such user").SetInternal(errors.New("no rows"))
+	})
+	e.POST("/users", func(c echo.Context) error {
+		return errors.New("boom")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/3", nil))
+	g.Expect(rec.Code).To(Equal(http.StatusNotFound))
+	g.Expect(rec.Body.String()).To(Equal(`{"message":"no such user"}` + "\n"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	g.Expect(lines).To(HaveLen(1)) // logged once, despite the Logger middleware
+
+	var m map[string]interface{}
+	g.Expect(json.Unmarshal([]byte(lines[0]), &m)).To(Succeed())
+	g.Expect(m).To(HaveKeyWithValue("level", "warn"))
+	g.Expect(m).To(HaveKeyWithValue("status", 404.0))
+	g.Expect(m).To(HaveKeyWithValue("route", "/users/:id"))
+	g.Expect(m).To(HaveKeyWithValue("method", "GET"))
+	g.Expect(m).To(HaveKeyWithValue("request_id", rec.Header().Get(echo.HeaderXRequestID)))
+	g.Expect(m).To(HaveKeyWithValue("internal", "no rows"))
+	g.Expect(m).To(HaveKeyWithValue("error", "code=404, message=no such user, internal=no rows"))
+	g.Expect(m).To(HaveKeyWithValue("message", "no such user"))
+	g.Expect(m).To(HaveKey("latency"))
+
+	buf.Reset()
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/users", nil))
+	g.Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+
+	m = nil
+	g.Expect(json.Unmarshal([]byte(buf.String()), &m)).To(Succeed())
+	g.Expect(m).To(HaveKeyWithValue("level", "error"))
+	g.Expect(m).To(HaveKeyWithValue("status", 500.0))
+	g.Expect(m).To(HaveKeyWithValue("error", "boom"))
+	g.Expect(m).To(HaveKeyWithValue("message", "Internal Server Error"))
+}
+
+func TestHTTPErrorHandler_custom(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	z := Wrap(zerolog.New(buf)).Level(zerolog.InfoLevel)
+
+	var handled error
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler(z, ErrorHandlerOptions{
+		Handler: func(err error, c echo.Context) {
+			handled = err
+			c.NoContent(http.StatusTeapot)
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	g.Expect(rec.Code).To(Equal(http.StatusTeapot))
+	g.Expect(handled).To(Equal(echo.ErrNotFound))
+	g.Expect(buf.String()).To(BeEmpty()) // 4xx are logged at debug level by default
+}
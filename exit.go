@@ -0,0 +1,50 @@
+package ech0
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit is the panic value used by PanicOnExit. It records the exit code.
+type Exit struct {
+	Code int
+}
+
+func (e Exit) String() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// PanicOnExit is an exit function for tests (see Log.SetExitFunc). Instead of
+// terminating the program, it panics with an Exit value, which can be recovered
+// to assert that the program would have terminated.
+func PanicOnExit(code int) {
+	panic(Exit{Code: code})
+}
+
+// Flush flushes each writer that buffers its output, i.e. that has a Flush or a Sync
+// method, such as async.Writer, EventWriter and os.File. Errors are ignored because
+// this is used just before the program exits.
+func Flush(ws ...io.Writer) {
+	for _, w := range ws {
+		switch f := w.(type) {
+		case interface{ Flush() error }:
+			f.Flush()
+		case interface{ Sync() error }:
+			f.Sync()
+		}
+	}
+}
+
+// Terminator is implemented by loggers that have their own way to end the program after
+// a Fatal event, such as a test logger that must not call os.Exit. Tee and Log use it.
+type Terminator interface {
+	Terminate(code int)
+}
+
+func exit(fn func(code int), code int) {
+	if fn == nil {
+		fn = os.Exit
+	}
+	fn(code)
+}
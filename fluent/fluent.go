@@ -0,0 +1,228 @@
+// Package fluent provides a writer that ships log events to Fluentd (or Fluent Bit)
+// using the Forward protocol, which is MessagePack over TCP.
+//
+// Events are queued by an async.Writer, so logging never blocks on the network.
+// Batches are sent in Forward mode, optionally waiting for an acknowledgement
+// from the server, and the connection is re-established with exponential
+// backoff when it fails.
+//
+//	w := fluent.New("tcp", "fluentd:24224", fluent.Options{RequireAck: true})
+//	defer w.Close()
+//	logger.SetOutput(w)
+package fluent
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rickb777/ech0/v3/async"
+	"github.com/rickb777/ech0/v3/internal/event"
+)
+
+const (
+	// DefaultTag is used when no tag is configured and the event has no prefix field.
+	DefaultTag = "ech0"
+	// PrefixFieldName is the event field that supplies the default tag. This is
+	// the field set by ech0.New from the Log prefix.
+	PrefixFieldName = "prefix"
+)
+
+// Options configures a Forwarder. The zero value is usable.
+type Options struct {
+	// Tag is the Fluentd tag for all events. If blank, the tag is taken from each
+	// event's prefix field (i.e. the Log prefix), or else DefaultTag.
+	Tag string
+	// RequireAck makes the forwarder wait for the server to acknowledge each batch.
+	RequireAck bool
+	// AckTimeout is how long to wait for an acknowledgement; default 5s.
+	AckTimeout time.Duration
+	// DialTimeout limits the time taken to connect; default 5s.
+	DialTimeout time.Duration
+	// MaxRetries is the number of times a failed batch is retried; default 5.
+	// Use a negative value for no retries.
+	MaxRetries int
+	// MinBackoff is the initial delay before reconnecting; default 100ms.
+	MinBackoff time.Duration
+	// MaxBackoff limits the delay between successive attempts; default 10s.
+	MaxBackoff time.Duration
+	// Async configures the queueing and batching of events.
+	Async async.Options
+}
+
+// Forwarder is an async.Sink that sends batches of events to a Forward server.
+// It is normally used via New, which wraps it in an async.Writer.
+type Forwarder struct {
+	network, address string
+	opts             Options
+	mu               sync.Mutex
+	conn             net.Conn
+	sleep            func(time.Duration)
+}
+
+var _ async.Sink = &Forwarder{}
+
+// New creates a writer that forwards events to the Fluentd server at address.
+// The connection is made lazily when the first batch is sent.
+func New(network, address string, opts Options) *async.Writer {
+	return async.New(NewForwarder(network, address, opts), opts.Async)
+}
+
+// NewForwarder creates a synchronous Forwarder. Most users will prefer New.
+func NewForwarder(network, address string, opts Options) *Forwarder {
+	if opts.AckTimeout <= 0 {
+		opts.AckTimeout = 5 * time.Second
+	}
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = 5 * time.Second
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = 5
+	} else if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = 100 * time.Millisecond
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = 10 * time.Second
+	}
+
+	return &Forwarder{network: network, address: address, opts: opts, sleep: time.Sleep}
+}
+
+// WriteBatch satisfies async.Sink. Events are grouped by tag and each group is
+// sent as one Forward mode message.
+func (f *Forwarder) WriteBatch(batch []async.Entry) error {
+	var tags []string
+	groups := make(map[string]encoder)
+	counts := make(map[string]int)
+
+	for _, e := range batch {
+		fields, err := event.Decode(e.Data)
+		if err != nil {
+			continue // not JSON; ignore it
+		}
+
+		t, ok := event.Time(fields)
+		if !ok {
+			t = time.Now()
+		}
+
+		tag := f.tag(fields)
+		if _, exists := groups[tag]; !exists {
+			tags = append(tags, tag)
+		}
+		groups[tag] = groups[tag].arrayHeader(2).eventTime(t).record(fields)
+		counts[tag]++
+	}
+
+	for _, tag := range tags {
+		if err := f.send(tag, counts[tag], groups[tag]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Forwarder) tag(fields map[string]interface{}) string {
+	if f.opts.Tag != "" {
+		return f.opts.Tag
+	}
+	if p, ok := fields[PrefixFieldName].(string); ok && p != "" {
+		return p
+	}
+	return DefaultTag
+}
+
+// send writes one Forward mode message: [tag, [[time, record]...], {option}].
+func (f *Forwarder) send(tag string, n int, entries encoder) error {
+	msg := encoder(nil).arrayHeader(3).str(tag).arrayHeader(n)
+	msg = append(msg, entries...)
+
+	var chunk string
+	if f.opts.RequireAck {
+		chunk = newChunkID()
+		msg = msg.mapHeader(2).str("size").int(int64(n)).str("chunk").str(chunk)
+	} else {
+		msg = msg.mapHeader(1).str("size").int(int64(n))
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	backoff := f.opts.MinBackoff
+	var err error
+	for attempt := 0; attempt <= f.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			f.sleep(backoff)
+			backoff *= 2
+			if backoff > f.opts.MaxBackoff {
+				backoff = f.opts.MaxBackoff
+			}
+		}
+
+		if err = f.attempt(msg, chunk); err == nil {
+			return nil
+		}
+		f.disconnect()
+	}
+	return err
+}
+
+func (f *Forwarder) attempt(msg []byte, chunk string) error {
+	if f.conn == nil {
+		conn, err := net.DialTimeout(f.network, f.address, f.opts.DialTimeout)
+		if err != nil {
+			return err
+		}
+		f.conn = conn
+	}
+
+	if _, err := f.conn.Write(msg); err != nil {
+		return err
+	}
+
+	if chunk == "" {
+		return nil
+	}
+
+	if err := f.conn.SetReadDeadline(time.Now().Add(f.opts.AckTimeout)); err != nil {
+		return err
+	}
+
+	resp, err := decodeValue(bufio.NewReader(f.conn))
+	if err != nil {
+		return err
+	}
+
+	if m, ok := resp.(map[string]interface{}); !ok || m["ack"] != chunk {
+		return fmt.Errorf("fluent: unexpected acknowledgement %v", resp)
+	}
+	return nil
+}
+
+func (f *Forwarder) disconnect() {
+	if f.conn != nil {
+		f.conn.Close()
+		f.conn = nil
+	}
+}
+
+// Close closes the connection, if any.
+func (f *Forwarder) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.disconnect()
+	return nil
+}
+
+func newChunkID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return base64.StdEncoding.EncodeToString(b)
+}
@@ -0,0 +1,146 @@
+package fluent
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	gommon "github.com/labstack/gommon/log"
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/ech0/v3"
+	"github.com/rickb777/ech0/v3/async"
+)
+
+// fakeServer is an in-process Forward protocol server. The first dropFirst
+// connections are closed without reading anything.
+type fakeServer struct {
+	ln        net.Listener
+	messages  chan []interface{}
+	dropFirst int
+}
+
+func newFakeServer(g *WithT, dropFirst int) *fakeServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+	s := &fakeServer{ln: ln, messages: make(chan []interface{}, 10), dropFirst: dropFirst}
+	go s.serve()
+	return s
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		if s.dropFirst > 0 {
+			s.dropFirst--
+			conn.Close()
+			continue
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		v, err := decodeValue(r)
+		if err != nil {
+			return
+		}
+		msg := v.([]interface{})
+		if option, ok := msg[2].(map[string]interface{}); ok {
+			if chunk, ok := option["chunk"].(string); ok {
+				conn.Write(encoder(nil).mapHeader(1).str("ack").str(chunk))
+			}
+		}
+		s.messages <- msg
+	}
+}
+
+func TestForward_with_prefix_tag(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s := newFakeServer(g, 0)
+	defer s.ln.Close()
+
+	w := New("tcp", s.ln.Addr().String(), Options{Async: async.Options{FlushInterval: time.Hour}})
+	l := ech0.New(w, "svc")
+	l.SetLevel(gommon.INFO)
+	l.Info("m1")
+	l.Warnj(gommon.JSON{"a": 1, "f": 1.5, "n": -100})
+	g.Expect(w.Close()).To(Succeed())
+
+	var msg []interface{}
+	g.Eventually(s.messages).Should(Receive(&msg))
+	g.Expect(msg[0]).To(Equal("svc"))
+
+	entries := msg[1].([]interface{})
+	g.Expect(entries).To(HaveLen(2))
+
+	e0 := entries[0].([]interface{})
+	g.Expect(e0[0]).To(BeAssignableToTypeOf(time.Time{}))
+	g.Expect(e0[1]).To(HaveKeyWithValue("message", "m1"))
+	g.Expect(e0[1]).To(HaveKeyWithValue("level", "info"))
+
+	e1 := entries[1].([]interface{})[1]
+	g.Expect(e1).To(HaveKeyWithValue("a", int64(1)))
+	g.Expect(e1).To(HaveKeyWithValue("f", 1.5))
+	g.Expect(e1).To(HaveKeyWithValue("n", int64(-100)))
+	g.Expect(msg[2]).To(HaveKeyWithValue("size", int64(2)))
+}
+
+func TestForward_ack_and_reconnect(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s := newFakeServer(g, 2)
+	defer s.ln.Close()
+
+	f := NewForwarder("tcp", s.ln.Addr().String(), Options{Tag: "app", RequireAck: true, AckTimeout: time.Second})
+	var delays []time.Duration
+	f.sleep = func(d time.Duration) { delays = append(delays, d) }
+
+	err := f.WriteBatch([]async.Entry{{Data: []byte(`{"level":"error","message":"m2"}`)}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(delays).To(Equal([]time.Duration{100 * time.Millisecond, 200 * time.Millisecond}))
+
+	var msg []interface{}
+	g.Eventually(s.messages).Should(Receive(&msg))
+	g.Expect(msg[0]).To(Equal("app"))
+	g.Expect(msg[2]).To(HaveKey("chunk"))
+	g.Expect(f.Close()).To(Succeed())
+}
+
+func TestForward_gives_up(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+	addr := ln.Addr().String()
+	ln.Close()
+
+	f := NewForwarder("tcp", addr, Options{MaxRetries: 2})
+	n := 0
+	f.sleep = func(time.Duration) { n++ }
+
+	err = f.WriteBatch([]async.Entry{{Data: []byte(`{"message":"m3"}`)}})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(n).To(Equal(2))
+}
+
+func TestForward_no_retries(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+	addr := ln.Addr().String()
+	ln.Close()
+
+	f := NewForwarder("tcp", addr, Options{MaxRetries: -1})
+	n := 0
+	f.sleep = func(time.Duration) { n++ }
+
+	err = f.WriteBatch([]async.Entry{{Data: []byte(`{"message":"m4"}`)}})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(n).To(Equal(0))
+	g.Expect(NewForwarder("tcp", addr, Options{}).opts.MaxRetries).To(Equal(5))
+}
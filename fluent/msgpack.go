@@ -0,0 +1,291 @@
+package fluent
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// encoder appends the small subset of MessagePack that the Forward protocol needs.
+type encoder []byte
+
+func (e encoder) arrayHeader(n int) encoder {
+	switch {
+	case n < 16:
+		return append(e, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return append(e, 0xdc, byte(n>>8), byte(n))
+	}
+	return append(e, 0xdd, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+func (e encoder) mapHeader(n int) encoder {
+	switch {
+	case n < 16:
+		return append(e, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return append(e, 0xde, byte(n>>8), byte(n))
+	}
+	return append(e, 0xdf, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+func (e encoder) str(s string) encoder {
+	n := len(s)
+	switch {
+	case n < 32:
+		e = append(e, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		e = append(e, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		e = append(e, 0xda, byte(n>>8), byte(n))
+	default:
+		e = append(e, 0xdb, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(e, s...)
+}
+
+func (e encoder) int(i int64) encoder {
+	if i >= 0 && i < 128 {
+		return append(e, byte(i))
+	}
+	if i < 0 && i >= -32 {
+		return append(e, byte(i))
+	}
+	e = append(e, 0xd3)
+	return e.uint64bits(uint64(i))
+}
+
+func (e encoder) uint(u uint64) encoder {
+	if u < 128 {
+		return append(e, byte(u))
+	}
+	e = append(e, 0xcf)
+	return e.uint64bits(u)
+}
+
+func (e encoder) float(f float64) encoder {
+	e = append(e, 0xcb)
+	return e.uint64bits(math.Float64bits(f))
+}
+
+func (e encoder) uint64bits(u uint64) encoder {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], u)
+	return append(e, b[:]...)
+}
+
+// eventTime appends the Forward protocol EventTime extension type, which
+// preserves nanosecond precision.
+func (e encoder) eventTime(t time.Time) encoder {
+	var b [8]byte
+	binary.BigEndian.PutUint32(b[:4], uint32(t.Unix()))
+	binary.BigEndian.PutUint32(b[4:], uint32(t.Nanosecond()))
+	e = append(e, 0xd7, 0x00)
+	return append(e, b[:]...)
+}
+
+// value appends any value produced by decoding JSON with json.Number enabled.
+func (e encoder) value(v interface{}) encoder {
+	switch x := v.(type) {
+	case nil:
+		return append(e, 0xc0)
+	case bool:
+		if x {
+			return append(e, 0xc3)
+		}
+		return append(e, 0xc2)
+	case string:
+		return e.str(x)
+	case json.Number:
+		if i, err := strconv.ParseInt(x.String(), 10, 64); err == nil {
+			return e.int(i)
+		}
+		if u, err := strconv.ParseUint(x.String(), 10, 64); err == nil {
+			return e.uint(u)
+		}
+		f, _ := x.Float64()
+		return e.float(f)
+	case []interface{}:
+		e = e.arrayHeader(len(x))
+		for _, item := range x {
+			e = e.value(item)
+		}
+		return e
+	case map[string]interface{}:
+		return e.record(x)
+	}
+	return e.str(fmt.Sprint(v))
+}
+
+// record appends a map with its keys in sorted order, so that output is repeatable.
+func (e encoder) record(m map[string]interface{}) encoder {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	e = e.mapHeader(len(m))
+	for _, k := range keys {
+		e = e.str(k).value(m[k])
+	}
+	return e
+}
+
+//-------------------------------------------------------------------------------------------------
+
+var errUnsupported = errors.New("fluent: unsupported msgpack type")
+
+// decodeValue reads one MessagePack value. It understands the same subset of
+// MessagePack as the encoder, which includes everything a Forward server sends
+// in its responses.
+func decodeValue(r *bufio.Reader) (interface{}, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case b <= 0x7f:
+		return int64(b), nil
+	case b >= 0xe0:
+		return int64(int8(b)), nil
+	case b&0xf0 == 0x80:
+		return decodeMap(r, int(b&0x0f))
+	case b&0xf0 == 0x90:
+		return decodeArray(r, int(b&0x0f))
+	case b&0xe0 == 0xa0:
+		return decodeStr(r, int(b&0x1f))
+	}
+
+	switch b {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xd9:
+		n, err := readN(r, 1)
+		if err != nil {
+			return nil, err
+		}
+		return decodeStr(r, n)
+	case 0xda:
+		n, err := readN(r, 2)
+		if err != nil {
+			return nil, err
+		}
+		return decodeStr(r, n)
+	case 0xdb:
+		n, err := readN(r, 4)
+		if err != nil {
+			return nil, err
+		}
+		return decodeStr(r, n)
+	case 0xde:
+		n, err := readN(r, 2)
+		if err != nil {
+			return nil, err
+		}
+		return decodeMap(r, n)
+	case 0xdf:
+		n, err := readN(r, 4)
+		if err != nil {
+			return nil, err
+		}
+		return decodeMap(r, n)
+	case 0xdc:
+		n, err := readN(r, 2)
+		if err != nil {
+			return nil, err
+		}
+		return decodeArray(r, n)
+	case 0xdd:
+		n, err := readN(r, 4)
+		if err != nil {
+			return nil, err
+		}
+		return decodeArray(r, n)
+	case 0xcb:
+		b, err := read8(r)
+		return math.Float64frombits(b), err
+	case 0xcf:
+		return read8(r)
+	case 0xd3:
+		b, err := read8(r)
+		return int64(b), err
+	case 0xd7:
+		ext, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		b, err := read8(r)
+		if err != nil || ext != 0 {
+			return nil, errUnsupported
+		}
+		return time.Unix(int64(b>>32), int64(b&0xffffffff)), nil
+	}
+	return nil, errUnsupported
+}
+
+func readN(r *bufio.Reader, size int) (int, error) {
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, x := range b {
+		n = n<<8 | int(x)
+	}
+	return n, nil
+}
+
+func read8(r *bufio.Reader) (uint64, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(b[:]), nil
+}
+
+func decodeStr(r *bufio.Reader, n int) (string, error) {
+	b := make([]byte, n)
+	_, err := io.ReadFull(r, b)
+	return string(b), err
+}
+
+func decodeArray(r *bufio.Reader, n int) ([]interface{}, error) {
+	a := make([]interface{}, n)
+	for i := range a {
+		v, err := decodeValue(r)
+		if err != nil {
+			return nil, err
+		}
+		a[i] = v
+	}
+	return a, nil
+}
+
+func decodeMap(r *bufio.Reader, n int) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		k, err := decodeValue(r)
+		if err != nil {
+			return nil, err
+		}
+		v, err := decodeValue(r)
+		if err != nil {
+			return nil, err
+		}
+		m[fmt.Sprint(k)] = v
+	}
+	return m, nil
+}
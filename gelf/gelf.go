@@ -0,0 +1,323 @@
+// Package gelf provides a zerolog.LevelWriter that sends log events to Graylog
+// as GELF 1.1 messages, over either UDP or TCP.
+//
+// Use it as the output of an ech0.Log:
+//
+//	w, err := gelf.Dial("udp", "graylog:12201", gelf.Options{})
+//	...
+//	logger.SetOutput(w)
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rickb777/ech0/v3/internal/event"
+	"github.com/rs/zerolog"
+)
+
+// Version is the GELF specification version that is emitted.
+const Version = "1.1"
+
+const (
+	// DefaultChunkSize is the default maximum UDP datagram size. It suits typical
+	// ethernet MTUs; use 8154 on networks that are known to allow larger datagrams.
+	DefaultChunkSize = 1420
+
+	chunkHeaderSize = 12
+	maxChunks       = 128
+)
+
+var chunkMagic = []byte{0x1e, 0x0f}
+
+// ErrTooLarge is returned when a UDP message would need more than 128 chunks.
+var ErrTooLarge = errors.New("gelf: message too large to send in 128 chunks")
+
+// Compression selects how UDP messages are compressed. TCP messages are never compressed
+// because GELF TCP uses null-byte framing.
+type Compression int
+
+const (
+	// Gzip compression is the default.
+	Gzip Compression = iota
+	// Zlib compression.
+	Zlib
+	// NoCompression sends UDP messages as plain JSON.
+	NoCompression
+)
+
+// Options configures a Writer. The zero value is usable.
+type Options struct {
+	// Host is reported as the GELF host; it defaults to os.Hostname.
+	Host string
+	// ChunkSize is the maximum UDP datagram size; it defaults to DefaultChunkSize.
+	ChunkSize int
+	// Compression applies to UDP only; it defaults to Gzip.
+	Compression Compression
+}
+
+// Writer converts zerolog JSON events into GELF messages and sends them to Graylog.
+// It is safe for concurrent use.
+type Writer struct {
+	network, address string
+	opts             Options
+	mu               sync.Mutex
+	conn             net.Conn
+}
+
+var _ zerolog.LevelWriter = &Writer{}
+
+// Dial connects to a Graylog GELF input. The network must be "udp" or "tcp"
+// (or one of their "4"/"6" variants).
+func Dial(network, address string, opts Options) (*Writer, error) {
+	if !strings.HasPrefix(network, "udp") && !strings.HasPrefix(network, "tcp") {
+		return nil, fmt.Errorf("gelf: unsupported network %q", network)
+	}
+
+	if opts.Host == "" {
+		opts.Host, _ = os.Hostname()
+	}
+	if opts.ChunkSize <= chunkHeaderSize {
+		opts.ChunkSize = DefaultChunkSize
+	}
+
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{network: network, address: address, opts: opts, conn: conn}, nil
+}
+
+// Write satisfies io.Writer. The level is taken from the event itself.
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel satisfies zerolog.LevelWriter.
+func (w *Writer) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	msg, err := w.Marshal(level, p)
+	if err != nil {
+		return 0, err
+	}
+
+	if w.isUDP() {
+		err = w.sendUDP(msg)
+	} else {
+		err = w.sendTCP(append(msg, 0))
+	}
+
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close closes the underlying connection.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.conn.Close()
+}
+
+func (w *Writer) isUDP() bool {
+	return strings.HasPrefix(w.network, "udp")
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// Marshal converts one zerolog JSON event into a GELF JSON message. If level is
+// zerolog.NoLevel, the level field of the event is used instead.
+func (w *Writer) Marshal(level zerolog.Level, p []byte) ([]byte, error) {
+	fields, err := event.Decode(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if level == zerolog.NoLevel {
+		level = event.Level(fields)
+	}
+
+	msg := map[string]interface{}{
+		"version": Version,
+		"host":    w.opts.Host,
+		"level":   SyslogLevel(level),
+	}
+
+	short := event.Message(fields)
+	if i := strings.IndexByte(short, '\n'); i >= 0 {
+		msg["full_message"] = short
+		short = short[:i]
+	}
+	if short == "" {
+		short = "-"
+	}
+	msg["short_message"] = short
+
+	t, ok := event.Time(fields)
+	if !ok {
+		t = time.Now()
+	}
+	msg["timestamp"] = json.Number(fmt.Sprintf("%.3f", float64(t.UnixNano())/float64(time.Second)))
+
+	delete(fields, zerolog.MessageFieldName)
+	delete(fields, zerolog.LevelFieldName)
+	delete(fields, zerolog.TimestampFieldName)
+
+	for k, v := range fields {
+		msg[additionalField(k)] = additionalValue(v)
+	}
+
+	return json.Marshal(msg)
+}
+
+// SyslogLevel maps a zerolog level to the syslog severity used by GELF.
+func SyslogLevel(level zerolog.Level) int {
+	switch level {
+	case zerolog.TraceLevel, zerolog.DebugLevel:
+		return 7
+	case zerolog.InfoLevel:
+		return 6
+	case zerolog.WarnLevel:
+		return 4
+	case zerolog.ErrorLevel:
+		return 3
+	case zerolog.FatalLevel:
+		return 2
+	case zerolog.PanicLevel:
+		return 0
+	}
+	return 6 // NoLevel is treated as informational
+}
+
+// additionalField prefixes a field name with '_' and replaces characters
+// that GELF does not allow. The name "_id" is reserved by GELF.
+func additionalField(k string) string {
+	k = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '_', r == '.', r == '-':
+			return r
+		}
+		return '_'
+	}, k)
+
+	if k == "id" {
+		return "__id"
+	}
+	return "_" + k
+}
+
+// additionalValue converts a field value to a string or number, which are the
+// only types allowed by GELF for additional fields.
+func additionalValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case string, json.Number:
+		return x
+	case bool:
+		return fmt.Sprint(x)
+	case nil:
+		return ""
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+//-------------------------------------------------------------------------------------------------
+
+func (w *Writer) sendTCP(msg []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err := w.conn.Write(msg)
+	if err == nil {
+		return nil
+	}
+
+	// the connection may have been dropped by the server; try once more on a new one
+	w.conn.Close()
+	conn, err := net.Dial(w.network, w.address)
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+	_, err = w.conn.Write(msg)
+	return err
+}
+
+func (w *Writer) sendUDP(msg []byte) error {
+	data, err := w.compress(msg)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(data) <= w.opts.ChunkSize {
+		_, err = w.conn.Write(data)
+		return err
+	}
+
+	size := w.opts.ChunkSize - chunkHeaderSize
+	count := (len(data) + size - 1) / size
+	if count > maxChunks {
+		return ErrTooLarge
+	}
+
+	id := make([]byte, 8)
+	if _, err = rand.Read(id); err != nil {
+		return err
+	}
+
+	chunk := make([]byte, 0, w.opts.ChunkSize)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * size
+		if end > len(data) {
+			end = len(data)
+		}
+
+		chunk = append(chunk[:0], chunkMagic...)
+		chunk = append(chunk, id...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, data[i*size:end]...)
+
+		if _, err = w.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Writer) compress(msg []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var zw io.WriteCloser
+
+	switch w.opts.Compression {
+	case Gzip:
+		zw = gzip.NewWriter(&buf)
+	case Zlib:
+		zw = zlib.NewWriter(&buf)
+	default:
+		return msg, nil
+	}
+
+	if _, err := zw.Write(msg); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
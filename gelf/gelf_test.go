@@ -0,0 +1,167 @@
+package gelf
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	gommon "github.com/labstack/gommon/log"
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/ech0/v3"
+	"github.com/rs/zerolog"
+)
+
+func TestUDP_single_datagram(t *testing.T) {
+	g := NewGomegaWithT(t)
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer pc.Close()
+
+	w, err := Dial("udp", pc.LocalAddr().String(), Options{Host: "h1"})
+	g.Expect(err).NotTo(HaveOccurred())
+	defer w.Close()
+
+	l := ech0.New(w, "svc")
+	l.SetLevel(gommon.DEBUG)
+	l.Warnj(gommon.JSON{"a": 1, "b": true, "id": "x", "n": map[string]interface{}{"c": "d"}})
+
+	buf := make([]byte, 65536)
+	n, _, err := pc.ReadFrom(buf)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	zr, err := gzip.NewReader(bytes.NewReader(buf[:n]))
+	g.Expect(err).NotTo(HaveOccurred())
+	msg := decode(g, zr)
+
+	g.Expect(msg["version"]).To(Equal("1.1"))
+	g.Expect(msg["host"]).To(Equal("h1"))
+	g.Expect(msg["level"]).To(Equal(4.0))
+	g.Expect(msg["short_message"]).To(Equal("-"))
+	g.Expect(msg["timestamp"]).To(BeNumerically(">", 0))
+	g.Expect(msg["_prefix"]).To(Equal("svc"))
+	g.Expect(msg["_a"]).To(Equal(1.0))
+	g.Expect(msg["_b"]).To(Equal("true"))
+	g.Expect(msg["__id"]).To(Equal("x"))
+	g.Expect(msg["_n"]).To(Equal(`{"c":"d"}`))
+	g.Expect(msg).NotTo(HaveKey("_level"))
+	g.Expect(msg).NotTo(HaveKey("_time"))
+}
+
+func TestUDP_chunked(t *testing.T) {
+	g := NewGomegaWithT(t)
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer pc.Close()
+
+	w, err := Dial("udp", pc.LocalAddr().String(), Options{Host: "h1", ChunkSize: 100, Compression: Zlib})
+	g.Expect(err).NotTo(HaveOccurred())
+	defer w.Close()
+
+	long := strings.Repeat("the quick brown fox jumps over the lazy dog ", 40)
+	z := zerolog.New(w)
+	z.Error().Str("long", long).Msg("first line\nsecond line")
+
+	var data []byte
+	var count byte = 1
+	for i := byte(0); i < count; i++ {
+		buf := make([]byte, 200)
+		n, _, err := pc.ReadFrom(buf)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(n).To(BeNumerically("<=", 100))
+		g.Expect(buf[:2]).To(Equal(chunkMagic))
+		g.Expect(buf[10]).To(Equal(i))
+		count = buf[11]
+		data = append(data, buf[12:n]...)
+	}
+	g.Expect(count).To(BeNumerically(">", 1))
+
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	g.Expect(err).NotTo(HaveOccurred())
+	msg := decode(g, zr)
+
+	g.Expect(msg["level"]).To(Equal(3.0))
+	g.Expect(msg["short_message"]).To(Equal("first line"))
+	g.Expect(msg["full_message"]).To(Equal("first line\nsecond line"))
+	g.Expect(msg["_long"]).To(Equal(long))
+}
+
+func TestUDP_too_large(t *testing.T) {
+	g := NewGomegaWithT(t)
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer pc.Close()
+
+	w, err := Dial("udp", pc.LocalAddr().String(), Options{ChunkSize: 20, Compression: NoCompression})
+	g.Expect(err).NotTo(HaveOccurred())
+	defer w.Close()
+
+	_, err = w.WriteLevel(zerolog.InfoLevel, []byte(`{"message":"`+strings.Repeat("x", 2000)+`"}`))
+	g.Expect(err).To(Equal(ErrTooLarge))
+}
+
+func TestTCP(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer ln.Close()
+
+	received := make(chan []byte, 2)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			frame, err := r.ReadBytes(0)
+			if err != nil {
+				return
+			}
+			received <- frame
+		}
+	}()
+
+	w, err := Dial("tcp", ln.Addr().String(), Options{Host: "h2"})
+	g.Expect(err).NotTo(HaveOccurred())
+	defer w.Close()
+
+	z := zerolog.New(w).With().Timestamp().Logger()
+	z.Info().Int("a", 1).Msg("m1")
+	z.Debug().Msg("m2")
+
+	for _, exp := range []struct {
+		msg   string
+		level float64
+	}{{"m1", 6}, {"m2", 7}} {
+		var frame []byte
+		g.Eventually(received, time.Second).Should(Receive(&frame))
+		g.Expect(frame[len(frame)-1]).To(Equal(byte(0)))
+
+		msg := decode(g, bytes.NewReader(frame[:len(frame)-1]))
+		g.Expect(msg["host"]).To(Equal("h2"))
+		g.Expect(msg["short_message"]).To(Equal(exp.msg))
+		g.Expect(msg["level"]).To(Equal(exp.level))
+	}
+}
+
+func TestDial_bad_network(t *testing.T) {
+	g := NewGomegaWithT(t)
+	_, err := Dial("unix", "/tmp/x", Options{})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func decode(g *WithT, r interface{ Read([]byte) (int, error) }) map[string]interface{} {
+	b, err := ioutil.ReadAll(r)
+	g.Expect(err).NotTo(HaveOccurred())
+	msg := make(map[string]interface{})
+	g.Expect(json.Unmarshal(b, &msg)).To(Succeed())
+	return msg
+}
@@ -0,0 +1,73 @@
+// Package event decodes the JSON log events emitted by zerolog so that they can be
+// re-encoded by the various output writers.
+package event
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Decode parses a single zerolog JSON event. Numbers are kept as json.Number so
+// that integers survive without loss of precision.
+func Decode(p []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	fields := make(map[string]interface{})
+	if err := dec.Decode(&fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// Level determines the level of an event from its level field. If the field is
+// absent or cannot be parsed, zerolog.NoLevel is returned.
+func Level(fields map[string]interface{}) zerolog.Level {
+	s, ok := fields[zerolog.LevelFieldName].(string)
+	if !ok {
+		return zerolog.NoLevel
+	}
+	lvl, err := zerolog.ParseLevel(s)
+	if err != nil {
+		return zerolog.NoLevel
+	}
+	return lvl
+}
+
+// Message returns the message field of an event, or the empty string.
+func Message(fields map[string]interface{}) string {
+	s, _ := fields[zerolog.MessageFieldName].(string)
+	return s
+}
+
+// Time returns the timestamp of an event, decoded according to zerolog.TimeFieldFormat.
+// The boolean is false if the event has no usable timestamp.
+func Time(fields map[string]interface{}) (time.Time, bool) {
+	switch v := fields[zerolog.TimestampFieldName].(type) {
+	case string:
+		if t, err := time.Parse(zerolog.TimeFieldFormat, v); err == nil {
+			return t, true
+		}
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t, true
+		}
+
+	case json.Number:
+		n, err := strconv.ParseInt(v.String(), 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		switch zerolog.TimeFieldFormat {
+		case zerolog.TimeFormatUnixMs:
+			return time.Unix(0, n*int64(time.Millisecond)), true
+		case zerolog.TimeFormatUnixMicro:
+			return time.Unix(0, n*int64(time.Microsecond)), true
+		default:
+			return time.Unix(n, 0), true
+		}
+	}
+	return time.Time{}, false
+}
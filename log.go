@@ -2,10 +2,12 @@ package ech0
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"path/filepath"
 	"runtime"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/gommon/log"
@@ -30,6 +32,14 @@ var (
 	}
 )
 
+var (
+	// StatusFieldName is the field that holds the status code of an echo.HTTPError.
+	StatusFieldName = "status"
+
+	// InternalErrorFieldName is the field that holds the internal error of an echo.HTTPError.
+	InternalErrorFieldName = "internal"
+)
+
 // Log wraps a zerolog.Logger to provide an `echo.Logger` implementation
 type Log struct {
 	prefix   string
@@ -37,6 +47,8 @@ type Log struct {
 	out      io.Writer
 	lvl      zerolog.Level
 	callsite bool
+	events   *EventWriter
+	exit     func(code int)
 }
 
 // New returns a new Log instance with the given output.
@@ -83,7 +95,8 @@ func (l Log) logWithFields() Zero {
 // Debug satisfies the echo.Logger interface
 func (l Log) Debug(i ...interface{}) {
 	ll := l.logWithFields()
-	ll.Debug().Msg(fmt.Sprint(i...))
+	ev, msg := withArgs(ll.Debug(), i)
+	ev.Msg(msg)
 }
 
 // Debugf satisfies the echo.Logger interface
@@ -107,7 +120,8 @@ func (l Log) Debugj(j log.JSON) {
 // Info satisfies the echo.Logger interface
 func (l Log) Info(i ...interface{}) {
 	ll := l.logWithFields()
-	ll.Info().Msg(fmt.Sprint(i...))
+	ev, msg := withArgs(ll.Info(), i)
+	ev.Msg(msg)
 }
 
 // Infof satisfies the echo.Logger interface
@@ -131,7 +145,8 @@ func (l Log) Infoj(j log.JSON) {
 // Warn satisfies the echo.Logger interface
 func (l Log) Warn(i ...interface{}) {
 	ll := l.logWithFields()
-	ll.Warn().Msg(fmt.Sprint(i...))
+	ev, msg := withArgs(ll.Warn(), i)
+	ev.Msg(msg)
 }
 
 //Warnf satisfies the echo.Logger interface
@@ -155,7 +170,8 @@ func (l Log) Warnj(j log.JSON) {
 // Error satisfies the echo.Logger interface
 func (l Log) Error(i ...interface{}) {
 	ll := l.logWithFields()
-	ll.Error().Msg(fmt.Sprint(i...))
+	ev, msg := withArgs(ll.Error(), i)
+	ev.Msg(msg)
 }
 
 // Errorf satisfies the echo.Logger interface
@@ -176,19 +192,25 @@ func (l Log) Errorj(j log.JSON) {
 
 }
 
-// Fatal satisfies the echo.Logger interface
+// Fatal satisfies the echo.Logger interface. After logging, the output is flushed
+// and the program exits; see SetExitFunc.
 func (l Log) Fatal(i ...interface{}) {
 	ll := l.logWithFields()
-	ll.Fatal().Msg(fmt.Sprint(i...))
+	ev, msg := withArgs(ll.WithLevel(zerolog.FatalLevel), i)
+	ev.Msg(msg)
+	l.terminate()
 }
 
-// Fatalf satisfies the echo.Logger interface
+// Fatalf satisfies the echo.Logger interface. After logging, the output is flushed
+// and the program exits; see SetExitFunc.
 func (l Log) Fatalf(format string, i ...interface{}) {
 	ll := l.logWithFields()
-	ll.Fatal().Msgf(format, i...)
+	ll.WithLevel(zerolog.FatalLevel).Msgf(format, i...)
+	l.terminate()
 }
 
-// Fatalj satisfies the echo.Logger interface
+// Fatalj satisfies the echo.Logger interface. After logging, the output is flushed
+// and the program exits; see SetExitFunc.
 func (l Log) Fatalj(j log.JSON) {
 	ll := l.logWithFields()
 	for k, v := range j {
@@ -196,14 +218,27 @@ func (l Log) Fatalj(j log.JSON) {
 		ll = ll.RawJSON(k, j)
 	}
 
-	ll.Fatal().Msg("")
+	ll.WithLevel(zerolog.FatalLevel).Msg("")
+	l.terminate()
+}
 
+func (l Log) terminate() {
+	if l.events != nil {
+		Flush(l.events)
+	}
+	Flush(l.out)
+	if term, ok := l.zl.(Terminator); ok && l.exit == nil {
+		term.Terminate(1)
+		return
+	}
+	exit(l.exit, 1)
 }
 
 // Panic satisfies the echo.Logger interface
 func (l Log) Panic(i ...interface{}) {
 	ll := l.logWithFields()
-	ll.Panic().Msg(fmt.Sprint(i...))
+	ev, msg := withArgs(ll.Panic(), i)
+	ev.Msg(msg)
 }
 
 // Panicf satisfies the echo.Logger interface
@@ -227,7 +262,8 @@ func (l Log) Panicj(j log.JSON) {
 // Print satisfies the echo.Logger interface
 func (l Log) Print(i ...interface{}) {
 	ll := l.logWithFields()
-	ll.WithLevel(zerolog.NoLevel).Str("level", "-").Msg(fmt.Sprint(i...))
+	ev, msg := withArgs(ll.WithLevel(zerolog.NoLevel).Str("level", "-"), i)
+	ev.Msg(msg)
 }
 
 // Printf satisfies the echo.Logger interface
@@ -246,8 +282,55 @@ func (l Log) Printj(j log.JSON) {
 	ll.WithLevel(zerolog.NoLevel).Str("level", "-").Msg("")
 }
 
-// Output satisfies the echo.Logger interface
+// withArgs adds any error arguments to the event as structured fields, and returns the
+// other arguments as the message. The first error is logged with Err; any others are logged
+// with AnErr as "error2", "error3" etc. To log the chain of the first error too, use a logger
+// whose context has Stack (see ZeroContext.Stack and Zero.ErrorStack).
+// An echo.HTTPError also adds its status code and internal error. Its message is used
+// if there are no other arguments.
+func withArgs(ev ZeroEvent, i []interface{}) (ZeroEvent, string) {
+	var rest []interface{}
+	var httpMsg interface{}
+	n := 0
+	for _, v := range i {
+		err, ok := v.(error)
+		if !ok {
+			rest = append(rest, v)
+			continue
+		}
+
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			ev = ev.Int(StatusFieldName, he.Code)
+			if he.Internal != nil {
+				ev = ev.AnErr(InternalErrorFieldName, he.Internal)
+			}
+			httpMsg = he.Message
+		}
+
+		n++
+		if n == 1 {
+			ev = ev.Err(err)
+		} else {
+			ev = ev.AnErr(zerolog.ErrorFieldName+strconv.Itoa(n), err)
+		}
+	}
+
+	if n == 0 {
+		return ev, fmt.Sprint(i...)
+	}
+	if len(rest) == 0 && httpMsg != nil {
+		return ev, fmt.Sprint(httpMsg)
+	}
+	return ev, fmt.Sprint(rest...)
+}
+
+// Output satisfies the echo.Logger interface. Normally, this returns the underlying
+// writer, but see SetOutputEvents.
 func (l Log) Output() io.Writer {
+	if l.events != nil {
+		return l.events
+	}
 	return l.out
 }
 
@@ -255,6 +338,27 @@ func (l Log) Output() io.Writer {
 func (l *Log) SetOutput(w io.Writer) {
 	l.zl = l.zl.Output(w)
 	l.out = w
+	l.updateEvents()
+}
+
+// SetOutputEvents makes Output return an EventWriter instead of the underlying writer.
+// So any raw text written to Output, such as echo's startup banner, is converted to log
+// events at the given level, with a "source" field if source is not blank.
+// Otherwise, raw text would be mixed into the JSON output.
+//
+// Set the level to zerolog.Disabled to revert to the default behaviour.
+func (l *Log) SetOutputEvents(level zerolog.Level, source string) {
+	if level == zerolog.Disabled {
+		l.events = nil
+	} else {
+		l.events = NewEventWriter(l.zl, level, source)
+	}
+}
+
+func (l *Log) updateEvents() {
+	if l.events != nil {
+		l.events.setZero(l.zl)
+	}
 }
 
 // Level satisfies the echo.Logger interface
@@ -267,6 +371,7 @@ func (l *Log) SetLevel(v log.Lvl) {
 	zlvl := gomLvlToZlvl[v]
 	l.zl = l.zl.Level(zlvl)
 	l.lvl = zlvl
+	l.updateEvents()
 }
 
 // Prefix satisfies the echo.Logger interface
@@ -295,6 +400,15 @@ func (l *Log) SetHeader(h string) {
 	// no-op
 }
 
+// SetExitFunc sets the function called after a Fatal event has been logged and the
+// output has been flushed. By default, the logger's Terminate method is used if it is
+// a Terminator, such as a testlogger.TestLogger or a Tee containing one, otherwise
+// os.Exit. In tests, PanicOnExit allows the termination to be detected. Set nil to
+// restore the default.
+func (l *Log) SetExitFunc(exit func(code int)) {
+	l.exit = exit
+}
+
 // SetCallsite controls whether file and line numbers are emitted with every
 // log output. Set this true to enable these items.
 func (l *Log) SetCallsite(enabled bool) {
@@ -3,9 +3,12 @@ package ech0
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/rs/zerolog"
 	"io/ioutil"
 	"math"
+	"net/http"
 	"reflect"
 	"strconv"
 	"strings"
@@ -14,6 +17,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	gommon "github.com/labstack/gommon/log"
+	. "github.com/onsi/gomega"
 )
 
 // zerolog output json
@@ -255,3 +259,79 @@ func bench(l echo.Logger, b *testing.B) {
 		l.Info("hello")
 	}
 }
+
+func TestErrorArgs(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	l := New(nil, "", Wrap(zerolog.New(buf)))
+
+	l.Error("failed:", errors.New("e1"), 5, errors.New("e2"))
+	g.Expect(buf.String()).To(Equal(`{"level":"error","error":"e1","error2":"e2","message":"failed:5"}`+"\n"), buf.String())
+
+	buf.Reset()
+	he := echo.NewHTTPError(http.StatusNotFound, "nope").SetInternal(errors.New("no row"))
+	l.Warn(fmt.Errorf("lookup: %w", he))
+	g.Expect(buf.String()).To(Equal(`{"level":"warn","status":404,"internal":"no row",`+
+		`"error":"lookup: code=404, message=nope, internal=no row","message":"nope"}`+"\n"), buf.String())
+
+	buf.Reset()
+	l.Info("a", 1)
+	g.Expect(buf.String()).To(Equal(`{"level":"info","message":"a1"}`+"\n"), buf.String())
+}
+
+func TestErrorArgs_chain(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	err := fmt.Errorf("x: %w", errors.New("e1"))
+
+	// by default, no stack is logged
+	New(buf, "").Error(err)
+	g.Expect(buf.String()).NotTo(ContainSubstring(`"stack"`), buf.String())
+
+	buf.Reset()
+	l := New(buf, "", Wrap(zerolog.New(buf)).With().Stack().Logger())
+	l.Error(err)
+	var m struct {
+		Error string       `json:"error"`
+		Stack []ErrorFrame `json:"stack"`
+	}
+	g.Expect(json.Unmarshal([]byte(buf.String()), &m)).To(Succeed())
+	g.Expect(m.Error).To(Equal("x: e1"))
+	g.Expect(m.Stack).To(HaveLen(2))
+	g.Expect(m.Stack[1].Type).To(Equal("*errors.errorString"))
+}
+
+type flushWriter struct {
+	strings.Builder
+	flushed int
+}
+
+func (w *flushWriter) Flush() error {
+	w.flushed++
+	return nil
+}
+
+func TestFatal_exit(t *testing.T) {
+	g := NewGomegaWithT(t)
+	w := &flushWriter{}
+	l := New(w, "", Wrap(zerolog.New(w)))
+	l.SetExitFunc(PanicOnExit)
+
+	g.Expect(func() { l.Fatal("bye") }).To(PanicWith(Exit{Code: 1}))
+	g.Expect(w.String()).To(Equal(`{"level":"fatal","message":"bye"}` + "\n"))
+	g.Expect(w.flushed).To(Equal(1))
+
+	g.Expect(func() { l.Fatalf("%s", "bye") }).To(PanicWith(Exit{Code: 1}))
+	g.Expect(w.flushed).To(Equal(2))
+}
+
+func TestFatal_terminator(t *testing.T) {
+	g := NewGomegaWithT(t)
+	w := &flushWriter{}
+	l := New(w, "", Tee(Wrap(zerolog.New(w)), terminating{Nop()}))
+
+	// without an exit function, the logger's Terminate method is used
+	g.Expect(func() { l.Fatal("bye") }).To(PanicWith(Exit{Code: 1}))
+	g.Expect(w.String()).To(Equal(`{"level":"fatal","message":"bye"}` + "\n"))
+	g.Expect(w.flushed).To(Equal(1))
+}
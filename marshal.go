@@ -0,0 +1,442 @@
+package ech0
+
+import (
+	"net"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ObjectMarshaler is implemented by types that can log themselves as a JSON object.
+// Because it is defined in terms of ZeroEvent, the same implementation works with
+// any logger that implements Zero, including the test logger.
+//
+// Fields should be added by calling methods on e; the return values can be ignored.
+type ObjectMarshaler interface {
+	MarshalZeroObject(e ZeroEvent)
+}
+
+// ArrayMarshaler is implemented by types that can log themselves as a JSON array.
+// Because it is defined in terms of ZeroArray, the same implementation works with
+// any logger that implements Zero, including the test logger.
+//
+// Elements should be added by calling methods on a; the return values can be ignored.
+type ArrayMarshaler interface {
+	MarshalZeroArray(a ZeroArray)
+}
+
+// ZeroArray mimics zerolog.Array. It is used to build an array, to be added to an
+// event using ZeroEvent.Array. Use Arr to create one.
+type ZeroArray interface {
+	ArrayMarshaler
+
+	// Object marshals an object that implements the ObjectMarshaler interface and appends it to the array.
+	Object(obj ObjectMarshaler) ZeroArray
+	// Str appends val as a string to the array.
+	Str(val string) ZeroArray
+	// Bytes appends val as a string to the array.
+	Bytes(val []byte) ZeroArray
+	// Hex appends val as a hex string to the array.
+	Hex(val []byte) ZeroArray
+	// RawJSON adds already encoded JSON to the array.
+	RawJSON(val []byte) ZeroArray
+	// Err serializes and appends the err to the array.
+	Err(err error) ZeroArray
+	// Bool appends the val as a bool to the array.
+	Bool(b bool) ZeroArray
+	// Int appends i as a int to the array.
+	Int(i int) ZeroArray
+	// Int8 appends i as a int8 to the array.
+	Int8(i int8) ZeroArray
+	// Int16 appends i as a int16 to the array.
+	Int16(i int16) ZeroArray
+	// Int32 appends i as a int32 to the array.
+	Int32(i int32) ZeroArray
+	// Int64 appends i as a int64 to the array.
+	Int64(i int64) ZeroArray
+	// Uint appends i as a uint to the array.
+	Uint(i uint) ZeroArray
+	// Uint8 appends i as a uint8 to the array.
+	Uint8(i uint8) ZeroArray
+	// Uint16 appends i as a uint16 to the array.
+	Uint16(i uint16) ZeroArray
+	// Uint32 appends i as a uint32 to the array.
+	Uint32(i uint32) ZeroArray
+	// Uint64 appends i as a uint64 to the array.
+	Uint64(i uint64) ZeroArray
+	// Float32 appends f as a float32 to the array.
+	Float32(f float32) ZeroArray
+	// Float64 appends f as a float64 to the array.
+	Float64(f float64) ZeroArray
+	// Time appends t formatted as string using zerolog.TimeFieldFormat.
+	Time(t time.Time) ZeroArray
+	// Dur appends d to the array.
+	Dur(d time.Duration) ZeroArray
+	// Interface appends i marshaled using reflection.
+	Interface(i interface{}) ZeroArray
+	// IPAddr adds IPv4 or IPv6 address to the array.
+	IPAddr(ip net.IP) ZeroArray
+	// IPPrefix adds IPv4 or IPv6 Prefix (IP + mask) to the array.
+	IPPrefix(pfx net.IPNet) ZeroArray
+	// MACAddr adds a MAC (Ethernet) address to the array.
+	MACAddr(ha net.HardwareAddr) ZeroArray
+}
+
+// Arr creates an array to be added to an event using the ZeroEvent.Array method.
+// Call usual element methods like Str, Int etc to add elements to the array.
+//
+// The elements are recorded and are only marshaled when the array is added to an event,
+// so the array can be used with any ZeroEvent implementation.
+func Arr() ZeroArray {
+	return &arrayRecorder{}
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// arrayRecorder remembers the elements that are added, in order to replay them later.
+type arrayRecorder []func(ZeroArray)
+
+var _ ZeroArray = &arrayRecorder{}
+
+// MarshalZeroArray replays the recorded elements onto t.
+func (a *arrayRecorder) MarshalZeroArray(t ZeroArray) {
+	for _, op := range *a {
+		op(t)
+	}
+}
+
+// Object marshals an object that implements the ObjectMarshaler interface and appends it to the array.
+func (a *arrayRecorder) Object(obj ObjectMarshaler) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Object(obj) })
+	return a
+}
+
+// Str appends val as a string to the array.
+func (a *arrayRecorder) Str(val string) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Str(val) })
+	return a
+}
+
+// Bytes appends val as a string to the array.
+func (a *arrayRecorder) Bytes(val []byte) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Bytes(val) })
+	return a
+}
+
+// Hex appends val as a hex string to the array.
+func (a *arrayRecorder) Hex(val []byte) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Hex(val) })
+	return a
+}
+
+// RawJSON adds already encoded JSON to the array.
+func (a *arrayRecorder) RawJSON(val []byte) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.RawJSON(val) })
+	return a
+}
+
+// Err serializes and appends the err to the array.
+func (a *arrayRecorder) Err(err error) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Err(err) })
+	return a
+}
+
+// Bool appends the val as a bool to the array.
+func (a *arrayRecorder) Bool(b bool) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Bool(b) })
+	return a
+}
+
+// Int appends i as a int to the array.
+func (a *arrayRecorder) Int(i int) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Int(i) })
+	return a
+}
+
+// Int8 appends i as a int8 to the array.
+func (a *arrayRecorder) Int8(i int8) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Int8(i) })
+	return a
+}
+
+// Int16 appends i as a int16 to the array.
+func (a *arrayRecorder) Int16(i int16) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Int16(i) })
+	return a
+}
+
+// Int32 appends i as a int32 to the array.
+func (a *arrayRecorder) Int32(i int32) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Int32(i) })
+	return a
+}
+
+// Int64 appends i as a int64 to the array.
+func (a *arrayRecorder) Int64(i int64) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Int64(i) })
+	return a
+}
+
+// Uint appends i as a uint to the array.
+func (a *arrayRecorder) Uint(i uint) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Uint(i) })
+	return a
+}
+
+// Uint8 appends i as a uint8 to the array.
+func (a *arrayRecorder) Uint8(i uint8) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Uint8(i) })
+	return a
+}
+
+// Uint16 appends i as a uint16 to the array.
+func (a *arrayRecorder) Uint16(i uint16) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Uint16(i) })
+	return a
+}
+
+// Uint32 appends i as a uint32 to the array.
+func (a *arrayRecorder) Uint32(i uint32) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Uint32(i) })
+	return a
+}
+
+// Uint64 appends i as a uint64 to the array.
+func (a *arrayRecorder) Uint64(i uint64) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Uint64(i) })
+	return a
+}
+
+// Float32 appends f as a float32 to the array.
+func (a *arrayRecorder) Float32(f float32) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Float32(f) })
+	return a
+}
+
+// Float64 appends f as a float64 to the array.
+func (a *arrayRecorder) Float64(f float64) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Float64(f) })
+	return a
+}
+
+// Time appends t formatted as string using zerolog.TimeFieldFormat.
+func (a *arrayRecorder) Time(t time.Time) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Time(t) })
+	return a
+}
+
+// Dur appends d to the array.
+func (a *arrayRecorder) Dur(d time.Duration) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Dur(d) })
+	return a
+}
+
+// Interface appends i marshaled using reflection.
+func (a *arrayRecorder) Interface(i interface{}) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.Interface(i) })
+	return a
+}
+
+// IPAddr adds IPv4 or IPv6 address to the array.
+func (a *arrayRecorder) IPAddr(ip net.IP) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.IPAddr(ip) })
+	return a
+}
+
+// IPPrefix adds IPv4 or IPv6 Prefix (IP + mask) to the array.
+func (a *arrayRecorder) IPPrefix(pfx net.IPNet) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.IPPrefix(pfx) })
+	return a
+}
+
+// MACAddr adds a MAC (Ethernet) address to the array.
+func (a *arrayRecorder) MACAddr(ha net.HardwareAddr) ZeroArray {
+	*a = append(*a, func(target ZeroArray) { target.MACAddr(ha) })
+	return a
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// zerologArray adapts a zerolog.Array to be the target of an ArrayMarshaler.
+type zerologArray zerolog.Array
+
+var _ ZeroArray = &zerologArray{}
+
+// MarshalZeroArray does nothing: the array's elements are already in the zerolog.Array.
+func (a *zerologArray) MarshalZeroArray(ZeroArray) {}
+
+// Object marshals an object that implements the ObjectMarshaler interface and appends it to the array.
+func (a *zerologArray) Object(obj ObjectMarshaler) ZeroArray {
+	(*zerolog.Array)(a).Object(objectMarshaler{obj: obj}) // arrays are not tied to a logger
+	return a
+}
+
+// Str appends val as a string to the array.
+func (a *zerologArray) Str(val string) ZeroArray {
+	(*zerolog.Array)(a).Str(val)
+	return a
+}
+
+// Bytes appends val as a string to the array.
+func (a *zerologArray) Bytes(val []byte) ZeroArray {
+	(*zerolog.Array)(a).Bytes(val)
+	return a
+}
+
+// Hex appends val as a hex string to the array.
+func (a *zerologArray) Hex(val []byte) ZeroArray {
+	(*zerolog.Array)(a).Hex(val)
+	return a
+}
+
+// RawJSON adds already encoded JSON to the array.
+func (a *zerologArray) RawJSON(val []byte) ZeroArray {
+	(*zerolog.Array)(a).RawJSON(val)
+	return a
+}
+
+// Err serializes and appends the err to the array.
+func (a *zerologArray) Err(err error) ZeroArray {
+	(*zerolog.Array)(a).Err(err)
+	return a
+}
+
+// Bool appends the val as a bool to the array.
+func (a *zerologArray) Bool(b bool) ZeroArray {
+	(*zerolog.Array)(a).Bool(b)
+	return a
+}
+
+// Int appends i as a int to the array.
+func (a *zerologArray) Int(i int) ZeroArray {
+	(*zerolog.Array)(a).Int(i)
+	return a
+}
+
+// Int8 appends i as a int8 to the array.
+func (a *zerologArray) Int8(i int8) ZeroArray {
+	(*zerolog.Array)(a).Int8(i)
+	return a
+}
+
+// Int16 appends i as a int16 to the array.
+func (a *zerologArray) Int16(i int16) ZeroArray {
+	(*zerolog.Array)(a).Int16(i)
+	return a
+}
+
+// Int32 appends i as a int32 to the array.
+func (a *zerologArray) Int32(i int32) ZeroArray {
+	(*zerolog.Array)(a).Int32(i)
+	return a
+}
+
+// Int64 appends i as a int64 to the array.
+func (a *zerologArray) Int64(i int64) ZeroArray {
+	(*zerolog.Array)(a).Int64(i)
+	return a
+}
+
+// Uint appends i as a uint to the array.
+func (a *zerologArray) Uint(i uint) ZeroArray {
+	(*zerolog.Array)(a).Uint(i)
+	return a
+}
+
+// Uint8 appends i as a uint8 to the array.
+func (a *zerologArray) Uint8(i uint8) ZeroArray {
+	(*zerolog.Array)(a).Uint8(i)
+	return a
+}
+
+// Uint16 appends i as a uint16 to the array.
+func (a *zerologArray) Uint16(i uint16) ZeroArray {
+	(*zerolog.Array)(a).Uint16(i)
+	return a
+}
+
+// Uint32 appends i as a uint32 to the array.
+func (a *zerologArray) Uint32(i uint32) ZeroArray {
+	(*zerolog.Array)(a).Uint32(i)
+	return a
+}
+
+// Uint64 appends i as a uint64 to the array.
+func (a *zerologArray) Uint64(i uint64) ZeroArray {
+	(*zerolog.Array)(a).Uint64(i)
+	return a
+}
+
+// Float32 appends f as a float32 to the array.
+func (a *zerologArray) Float32(f float32) ZeroArray {
+	(*zerolog.Array)(a).Float32(f)
+	return a
+}
+
+// Float64 appends f as a float64 to the array.
+func (a *zerologArray) Float64(f float64) ZeroArray {
+	(*zerolog.Array)(a).Float64(f)
+	return a
+}
+
+// Time appends t formatted as string using zerolog.TimeFieldFormat.
+func (a *zerologArray) Time(t time.Time) ZeroArray {
+	(*zerolog.Array)(a).Time(t)
+	return a
+}
+
+// Dur appends d to the array.
+func (a *zerologArray) Dur(d time.Duration) ZeroArray {
+	(*zerolog.Array)(a).Dur(d)
+	return a
+}
+
+// Interface appends i marshaled using reflection.
+func (a *zerologArray) Interface(i interface{}) ZeroArray {
+	(*zerolog.Array)(a).Interface(i)
+	return a
+}
+
+// IPAddr adds IPv4 or IPv6 address to the array.
+func (a *zerologArray) IPAddr(ip net.IP) ZeroArray {
+	(*zerolog.Array)(a).IPAddr(ip)
+	return a
+}
+
+// IPPrefix adds IPv4 or IPv6 Prefix (IP + mask) to the array.
+func (a *zerologArray) IPPrefix(pfx net.IPNet) ZeroArray {
+	(*zerolog.Array)(a).IPPrefix(pfx)
+	return a
+}
+
+// MACAddr adds a MAC (Ethernet) address to the array.
+func (a *zerologArray) MACAddr(ha net.HardwareAddr) ZeroArray {
+	(*zerolog.Array)(a).MACAddr(ha)
+	return a
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// objectMarshaler adapts an ObjectMarshaler to zerolog. The object's fields are added
+// using the StackMarshaler of the logger that created the event.
+type objectMarshaler struct {
+	obj   ObjectMarshaler
+	stack StackMarshaler
+}
+
+func (m objectMarshaler) MarshalZerologObject(e *zerolog.Event) {
+	if m.stack == nil {
+		m.obj.MarshalZeroObject((*zeroEvent)(e))
+	} else {
+		m.obj.MarshalZeroObject(&stackEvent{e: e, stack: m.stack})
+	}
+}
+
+// arrayMarshaler adapts an ArrayMarshaler to zerolog.
+type arrayMarshaler struct {
+	arr ArrayMarshaler
+}
+
+func (m arrayMarshaler) MarshalZerologArray(a *zerolog.Array) {
+	m.arr.MarshalZeroArray((*zerologArray)(a))
+}
@@ -0,0 +1,106 @@
+package ech0
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+type user struct {
+	name string
+	age  int
+}
+
+func (u user) MarshalZeroObject(e ZeroEvent) {
+	e.Str("name", u.name)
+	e.Int("age", u.age)
+}
+
+type users []user
+
+func (us users) MarshalZeroArray(a ZeroArray) {
+	for _, u := range us {
+		a.Object(u)
+	}
+}
+
+func TestObjectAndArray(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	z := Wrap(zerolog.New(buf))
+
+	z.Info().
+		Object("u", user{"Ann", 30}).
+		EmbedObject(user{"Bob", 40}).
+		Array("us", users{{"Cy", 50}}).
+		Array("a", Arr().Str("x").Int(1).Bool(true).Err(e1)).
+		Send()
+
+	g.Expect(buf.String()).To(Equal(`{"level":"info","u":{"name":"Ann","age":30},"name":"Bob","age":40,` +
+		`"us":[{"name":"Cy","age":50}],"a":["x",1,true,"x1"]` + newline))
+}
+
+func TestArr_is_reusable(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	z := Wrap(zerolog.New(buf))
+
+	a := Arr().Str("x").Object(user{"Di", 20})
+	z.Info().Array("a", a).Send()
+	z.Info().Array("b", a).Send()
+
+	g.Expect(buf.String()).To(Equal(`{"level":"info","a":["x",{"name":"Di","age":20}]` + newline +
+		`{"level":"info","b":["x",{"name":"Di","age":20}]` + newline))
+}
+
+func TestDict(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	z := Wrap(zerolog.New(buf))
+
+	inner := Dict().Str("c", "3")
+	d := Dict().Int("a", 1).Func(func(e ZeroEvent) { e.Bool("b", true) }).Dict("inner", inner)
+
+	z.Info().Dict("d", d).Dict("empty", Dict()).Dict("ignored", Disabled()).Msg("m")
+	g.Expect(buf.String()).To(Equal(`{"level":"info","d":{"a":1,"b":true,"inner":{"c":"3"}},"empty":{},"message":"m"}` + "\n"))
+
+	// the dictionary can be used again
+	buf.Reset()
+	z.Warn().Dict("d", d).Send()
+	g.Expect(buf.String()).To(Equal(`{"level":"warn","d":{"a":1,"b":true,"inner":{"c":"3"}}}` + "\n"))
+}
+
+// otherEvent is a ZeroEvent that cannot be used as a dictionary.
+type otherEvent struct {
+	ZeroEvent
+}
+
+func (otherEvent) String() string {
+	return "other"
+}
+
+func TestDict_otherEvents(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	z := Wrap(zerolog.New(buf))
+	dicts := Wrap(zerolog.New(ioutil.Discard))
+
+	z.Info().
+		Dict("w", dicts.Warn().Int("a", 1)).
+		Dict("t", Tee(dicts, dicts.Level(zerolog.Disabled)).Log().Str("b", "2")).
+		Dict("x", otherEvent{}).
+		Send()
+	g.Expect(buf.String()).To(Equal(`{"level":"info","x":"other"}` + "\n"))
+
+	buf.Reset()
+	z.With().
+		Dict("w", dicts.Log().Int("a", 1)).
+		Dict("t", Tee(dicts, dicts).Log().Str("b", "2")).
+		Dict("x", otherEvent{}).
+		Dict("ignored", Disabled()).
+		Logger().Info().Send()
+	g.Expect(buf.String()).To(Equal(`{"level":"info","x":"other"}` + "\n"))
+}
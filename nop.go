@@ -0,0 +1,88 @@
+package ech0
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+var nop = Wrap(zerolog.Nop())
+
+// Nop returns a logger that discards everything. It is a safe default
+// wherever a logger is optional.
+func Nop() Zero {
+	return nop
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// disabledEvent is a ZeroEvent that discards everything. Being empty,
+// it can be used as an interface value without allocation.
+type disabledEvent struct{}
+
+var _ ZeroEvent = disabledEvent{}
+
+// Disabled returns an event that is not enabled; all of its methods are no-ops.
+// This is the event returned by loggers whose level excludes the event.
+func Disabled() ZeroEvent {
+	return disabledEvent{}
+}
+
+func (disabledEvent) Send()                                                         {}
+func (disabledEvent) Msg(s string)                                                  {}
+func (disabledEvent) Msgf(format string, v ...interface{})                          {}
+func (disabledEvent) Enabled() bool                                                 { return false }
+func (d disabledEvent) Discard() ZeroEvent                                          { return d }
+func (d disabledEvent) Func(f func(e ZeroEvent)) ZeroEvent                          { return d }
+func (d disabledEvent) AnErr(key string, err error) ZeroEvent                       { return d }
+func (d disabledEvent) Bool(key string, val bool) ZeroEvent                         { return d }
+func (d disabledEvent) Bools(key string, b []bool) ZeroEvent                        { return d }
+func (d disabledEvent) Bytes(key string, val []byte) ZeroEvent                      { return d }
+func (d disabledEvent) Dict(key string, dict ZeroEvent) ZeroEvent                   { return d }
+func (d disabledEvent) Object(key string, obj ObjectMarshaler) ZeroEvent            { return d }
+func (d disabledEvent) EmbedObject(obj ObjectMarshaler) ZeroEvent                   { return d }
+func (d disabledEvent) Array(key string, arr ArrayMarshaler) ZeroEvent              { return d }
+func (d disabledEvent) Dur(key string, val time.Duration) ZeroEvent                 { return d }
+func (d disabledEvent) Err(err error) ZeroEvent                                     { return d }
+func (d disabledEvent) Stack() ZeroEvent                                            { return d }
+func (d disabledEvent) Caller(skip ...int) ZeroEvent                                { return d }
+func (d disabledEvent) Hex(key string, val []byte) ZeroEvent                        { return d }
+func (d disabledEvent) Int(key string, val int) ZeroEvent                           { return d }
+func (d disabledEvent) Ints(key string, val []int) ZeroEvent                        { return d }
+func (d disabledEvent) Int64(key string, val int64) ZeroEvent                       { return d }
+func (d disabledEvent) Interface(key string, val interface{}) ZeroEvent             { return d }
+func (d disabledEvent) Str(key string, val string) ZeroEvent                        { return d }
+func (d disabledEvent) Strs(key string, val []string) ZeroEvent                     { return d }
+func (d disabledEvent) Stringer(key string, val fmt.Stringer) ZeroEvent             { return d }
+func (d disabledEvent) Time(key string, val time.Time) ZeroEvent                    { return d }
+func (d disabledEvent) Timestamp() ZeroEvent                                        { return d }
+func (d disabledEvent) Uint(key string, val uint) ZeroEvent                         { return d }
+func (d disabledEvent) Uints(key string, val []uint) ZeroEvent                      { return d }
+func (d disabledEvent) Uint64(key string, val uint64) ZeroEvent                     { return d }
+func (d disabledEvent) Errs(key string, errs []error) ZeroEvent                     { return d }
+func (d disabledEvent) Float32(key string, val float32) ZeroEvent                   { return d }
+func (d disabledEvent) Floats32(key string, val []float32) ZeroEvent                { return d }
+func (d disabledEvent) Float64(key string, val float64) ZeroEvent                   { return d }
+func (d disabledEvent) Floats64(key string, val []float64) ZeroEvent                { return d }
+func (d disabledEvent) Int8(key string, val int8) ZeroEvent                         { return d }
+func (d disabledEvent) Int16(key string, val int16) ZeroEvent                       { return d }
+func (d disabledEvent) Int32(key string, val int32) ZeroEvent                       { return d }
+func (d disabledEvent) Ints8(key string, val []int8) ZeroEvent                      { return d }
+func (d disabledEvent) Ints16(key string, val []int16) ZeroEvent                    { return d }
+func (d disabledEvent) Ints32(key string, val []int32) ZeroEvent                    { return d }
+func (d disabledEvent) Ints64(key string, val []int64) ZeroEvent                    { return d }
+func (d disabledEvent) Uint8(key string, val uint8) ZeroEvent                       { return d }
+func (d disabledEvent) Uint16(key string, val uint16) ZeroEvent                     { return d }
+func (d disabledEvent) Uint32(key string, val uint32) ZeroEvent                     { return d }
+func (d disabledEvent) Uints8(key string, val []uint8) ZeroEvent                    { return d }
+func (d disabledEvent) Uints16(key string, val []uint16) ZeroEvent                  { return d }
+func (d disabledEvent) Uints32(key string, val []uint32) ZeroEvent                  { return d }
+func (d disabledEvent) Uints64(key string, val []uint64) ZeroEvent                  { return d }
+func (d disabledEvent) Times(key string, val []time.Time) ZeroEvent                 { return d }
+func (d disabledEvent) Durs(key string, val []time.Duration) ZeroEvent              { return d }
+func (d disabledEvent) TimeDiff(key string, t time.Time, start time.Time) ZeroEvent { return d }
+func (d disabledEvent) IPAddr(key string, ip net.IP) ZeroEvent                      { return d }
+func (d disabledEvent) IPPrefix(key string, pfx net.IPNet) ZeroEvent                { return d }
+func (d disabledEvent) MACAddr(key string, ha net.HardwareAddr) ZeroEvent           { return d }
@@ -0,0 +1,89 @@
+package ech0
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+func TestNop(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ev := Nop().Info()
+	g.Expect(ev.Enabled()).To(BeFalse())
+	ev.Str("a", "1").Dict("d", Dict().Int("b", 2)).Err(errors.New("e1")).Msg("m")
+
+	called := false
+	Nop().With().Str("a", "1").Logger().Error().Func(func(e ZeroEvent) { called = true }).Send()
+	g.Expect(called).To(BeFalse())
+}
+
+func TestDisabledLevel(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	z := Wrap(zerolog.New(buf)).Level(zerolog.WarnLevel)
+
+	g.Expect(z.Info()).To(Equal(Disabled()))
+	g.Expect(z.WithLevel(zerolog.Disabled)).To(Equal(Disabled()))
+	z.Info().Str("a", "1").Msg("m")
+	g.Expect(buf.String()).To(BeEmpty())
+}
+
+func TestCtx(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	z := Wrap(zerolog.New(buf))
+
+	g.Expect(Ctx(context.Background())).To(BeIdenticalTo(Nop()))
+
+	ctx := WithContext(context.Background(), z)
+	Ctx(ctx).Info().Msg("m")
+	g.Expect(buf.String()).To(Equal(`{"level":"info","message":"m"}` + "\n"))
+}
+
+func TestZeroAllocs(t *testing.T) {
+	g := NewGomegaWithT(t)
+	z := Wrap(zerolog.New(ioutil.Discard)).Level(zerolog.InfoLevel)
+
+	g.Expect(testing.AllocsPerRun(100, func() {
+		z.Debug().Str("a", "1").Int("b", 2).Msg("m")
+	})).To(BeZero())
+
+	g.Expect(testing.AllocsPerRun(100, func() {
+		Nop().Error().Str("a", "1").Int("b", 2).Msg("m")
+	})).To(BeZero())
+
+	// enabled events are not wrapped unless the logger's stack settings need it
+	g.Expect(testing.AllocsPerRun(100, func() {
+		z.Info().Str("a", "1").Int("b", 2).Msg("m")
+	})).To(BeZero())
+}
+
+func BenchmarkNop(b *testing.B) {
+	b.ReportAllocs()
+	z := Nop()
+	for i := 0; i < b.N; i++ {
+		z.Info().Str("a", "1").Int("b", 2).Msg("m")
+	}
+}
+
+func BenchmarkDisabledLevel(b *testing.B) {
+	b.ReportAllocs()
+	z := Wrap(zerolog.New(ioutil.Discard)).Level(zerolog.WarnLevel)
+	for i := 0; i < b.N; i++ {
+		z.Info().Str("a", "1").Int("b", 2).Msg("m")
+	}
+}
+
+func BenchmarkEnabled(b *testing.B) {
+	b.ReportAllocs()
+	z := Wrap(zerolog.New(ioutil.Discard))
+	for i := 0; i < b.N; i++ {
+		z.Info().Str("a", "1").Int("b", 2).Msg("m")
+	}
+}
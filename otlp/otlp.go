@@ -0,0 +1,311 @@
+// Package otlp exports log events to an OpenTelemetry collector using OTLP/HTTP
+// with JSON encoding. This lets echo services that log via ech0 join an OTel
+// logging pipeline.
+//
+// Each event becomes a LogRecord whose severity is derived from the zerolog level,
+// whose body is the message and whose attributes are the other fields. Trace and
+// span IDs are taken from well-known fields, so that logs correlate with traces.
+//
+//	w := otlp.New("http://collector:4318", otlp.Options{
+//		Resource: map[string]interface{}{"service.name": "orders"},
+//	})
+//	defer w.Close()
+//	logger.SetOutput(w)
+package otlp
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rickb777/ech0/v3/async"
+	"github.com/rickb777/ech0/v3/bulk"
+	"github.com/rickb777/ech0/v3/internal/event"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// LogsPath is appended to the endpoint given to New.
+	LogsPath = "/v1/logs"
+	// DefaultScopeName names the instrumentation scope of every LogRecord.
+	DefaultScopeName = "github.com/rickb777/ech0"
+	// DefaultTraceIDField is the event field that holds the trace ID.
+	DefaultTraceIDField = "trace_id"
+	// DefaultSpanIDField is the event field that holds the span ID.
+	DefaultSpanIDField = "span_id"
+)
+
+// Options configures the exporter. The zero value is usable.
+type Options struct {
+	// Resource attributes describe the service, e.g. "service.name".
+	Resource map[string]interface{}
+	// ScopeName defaults to DefaultScopeName.
+	ScopeName string
+	// TraceIDField defaults to DefaultTraceIDField. The value must be 32 hex digits.
+	TraceIDField string
+	// SpanIDField defaults to DefaultSpanIDField. The value must be 16 hex digits.
+	SpanIDField string
+	// Bulk configures the HTTP client, retries, batching etc.
+	Bulk bulk.Options
+}
+
+// New creates a writer that exports events to the OTLP/HTTP collector at endpoint,
+// e.g. "http://localhost:4318".
+func New(endpoint string, opts Options) *async.Writer {
+	url := strings.TrimSuffix(endpoint, "/") + LogsPath
+	return bulk.New(url, NewFormat(opts), opts.Bulk)
+}
+
+// Format is a bulk.Format that encodes batches as OTLP ExportLogsServiceRequest messages.
+type Format struct {
+	resource     []keyValue
+	scopeName    string
+	traceIDField string
+	spanIDField  string
+}
+
+var _ bulk.Format = Format{}
+
+// NewFormat creates a Format. Only the Bulk options are ignored.
+func NewFormat(opts Options) Format {
+	if opts.ScopeName == "" {
+		opts.ScopeName = DefaultScopeName
+	}
+	if opts.TraceIDField == "" {
+		opts.TraceIDField = DefaultTraceIDField
+	}
+	if opts.SpanIDField == "" {
+		opts.SpanIDField = DefaultSpanIDField
+	}
+
+	return Format{
+		resource:     attributes(opts.Resource),
+		scopeName:    opts.ScopeName,
+		traceIDField: opts.TraceIDField,
+		spanIDField:  opts.SpanIDField,
+	}
+}
+
+//-------------------------------------------------------------------------------------------------
+
+type exportRequest struct {
+	ResourceLogs []resourceLogs `json:"resourceLogs"`
+}
+
+type resourceLogs struct {
+	Resource  resource    `json:"resource"`
+	ScopeLogs []scopeLogs `json:"scopeLogs"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeLogs struct {
+	Scope      scope       `json:"scope"`
+	LogRecords []logRecord `json:"logRecords"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type logRecord struct {
+	TimeUnixNano         string     `json:"timeUnixNano"`
+	ObservedTimeUnixNano string     `json:"observedTimeUnixNano"`
+	SeverityNumber       int        `json:"severityNumber,omitempty"`
+	SeverityText         string     `json:"severityText,omitempty"`
+	Body                 *anyValue  `json:"body,omitempty"`
+	Attributes           []keyValue `json:"attributes"`
+	TraceID              string     `json:"traceId,omitempty"`
+	SpanID               string     `json:"spanId,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+// anyValue follows the protobuf JSON mapping, in which 64-bit integers are strings.
+type anyValue struct {
+	StringValue *string      `json:"stringValue,omitempty"`
+	BoolValue   *bool        `json:"boolValue,omitempty"`
+	IntValue    string       `json:"intValue,omitempty"`
+	DoubleValue *float64     `json:"doubleValue,omitempty"`
+	ArrayValue  *arrayValue  `json:"arrayValue,omitempty"`
+	KvlistValue *kvlistValue `json:"kvlistValue,omitempty"`
+}
+
+type arrayValue struct {
+	Values []anyValue `json:"values"`
+}
+
+type kvlistValue struct {
+	Values []keyValue `json:"values"`
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// Encode satisfies bulk.Format.
+func (f Format) Encode(batch []async.Entry) ([]byte, string, error) {
+	now := strconv.FormatInt(time.Now().UnixNano(), 10)
+	records := make([]logRecord, 0, len(batch))
+
+	for _, e := range batch {
+		fields, err := event.Decode(e.Data)
+		if err != nil {
+			continue // not JSON; ignore it
+		}
+		records = append(records, f.record(e.Level, fields, now))
+	}
+
+	req := exportRequest{ResourceLogs: []resourceLogs{{
+		Resource:  resource{Attributes: f.resource},
+		ScopeLogs: []scopeLogs{{Scope: scope{Name: f.scopeName}, LogRecords: records}},
+	}}}
+
+	body, err := json.Marshal(req)
+	return body, "application/json", err
+}
+
+func (f Format) record(level zerolog.Level, fields map[string]interface{}, now string) logRecord {
+	rec := logRecord{ObservedTimeUnixNano: now, TimeUnixNano: now}
+
+	if t, ok := event.Time(fields); ok {
+		rec.TimeUnixNano = strconv.FormatInt(t.UnixNano(), 10)
+	}
+
+	if level == zerolog.NoLevel {
+		level = event.Level(fields)
+	}
+	rec.SeverityNumber = SeverityNumber(level)
+	if level != zerolog.NoLevel {
+		rec.SeverityText = strings.ToUpper(level.String())
+	}
+
+	if msg, ok := fields[zerolog.MessageFieldName]; ok {
+		v := value(msg)
+		rec.Body = &v
+	}
+
+	delete(fields, zerolog.MessageFieldName)
+	delete(fields, zerolog.LevelFieldName)
+	delete(fields, zerolog.TimestampFieldName)
+
+	if id, ok := hexID(fields[f.traceIDField], 16); ok {
+		rec.TraceID = id
+		delete(fields, f.traceIDField)
+	}
+	if id, ok := hexID(fields[f.spanIDField], 8); ok {
+		rec.SpanID = id
+		delete(fields, f.spanIDField)
+	}
+
+	rec.Attributes = attributes(fields)
+	return rec
+}
+
+// Check satisfies bulk.Format. The collector may report a partial success.
+func (f Format) Check(body []byte) error {
+	var res struct {
+		PartialSuccess struct {
+			RejectedLogRecords json.Number `json:"rejectedLogRecords"`
+			ErrorMessage       string      `json:"errorMessage"`
+		} `json:"partialSuccess"`
+	}
+
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil
+	}
+
+	if n, _ := res.PartialSuccess.RejectedLogRecords.Int64(); n > 0 {
+		return fmt.Errorf("otlp: %d log records rejected: %s", n, res.PartialSuccess.ErrorMessage)
+	}
+	return nil
+}
+
+// SeverityNumber maps a zerolog level to the OpenTelemetry severity number.
+func SeverityNumber(level zerolog.Level) int {
+	switch level {
+	case zerolog.TraceLevel:
+		return 1
+	case zerolog.DebugLevel:
+		return 5
+	case zerolog.InfoLevel:
+		return 9
+	case zerolog.WarnLevel:
+		return 13
+	case zerolog.ErrorLevel:
+		return 17
+	case zerolog.FatalLevel:
+		return 21
+	case zerolog.PanicLevel:
+		return 24
+	}
+	return 0 // unspecified
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// hexID accepts a hex ID of the required number of bytes, which must not be all zero.
+func hexID(v interface{}, size int) (string, bool) {
+	s, ok := v.(string)
+	if !ok || len(s) != size*2 {
+		return "", false
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil || strings.Trim(s, "0") == "" {
+		return "", false
+	}
+	return hex.EncodeToString(b), true
+}
+
+// attributes converts a map to key-values, sorted by key so that output is repeatable.
+func attributes(m map[string]interface{}) []keyValue {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]keyValue, 0, len(m))
+	for _, k := range keys {
+		kvs = append(kvs, keyValue{Key: k, Value: value(m[k])})
+	}
+	return kvs
+}
+
+func value(v interface{}) anyValue {
+	switch x := v.(type) {
+	case string:
+		return anyValue{StringValue: &x}
+	case bool:
+		return anyValue{BoolValue: &x}
+	case json.Number:
+		if _, err := strconv.ParseInt(x.String(), 10, 64); err == nil {
+			return anyValue{IntValue: x.String()}
+		}
+		f, _ := x.Float64()
+		return anyValue{DoubleValue: &f}
+	case int:
+		return anyValue{IntValue: strconv.Itoa(x)}
+	case float64:
+		return anyValue{DoubleValue: &x}
+	case []interface{}:
+		arr := &arrayValue{Values: make([]anyValue, len(x))}
+		for i, item := range x {
+			arr.Values[i] = value(item)
+		}
+		return anyValue{ArrayValue: arr}
+	case map[string]interface{}:
+		return anyValue{KvlistValue: &kvlistValue{Values: attributes(x)}}
+	case nil:
+		return anyValue{}
+	}
+	s := fmt.Sprint(v)
+	return anyValue{StringValue: &s}
+}
@@ -0,0 +1,106 @@
+package otlp
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gommon "github.com/labstack/gommon/log"
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/ech0/v3"
+	"github.com/rickb777/ech0/v3/async"
+	"github.com/rickb777/ech0/v3/bulk"
+	"github.com/rs/zerolog"
+)
+
+func TestExport(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var path string
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		body, _ = ioutil.ReadAll(r.Body)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	w := New(srv.URL, Options{
+		Resource: map[string]interface{}{"service.name": "orders"},
+		Bulk:     bulk.Options{Async: async.Options{FlushInterval: time.Hour}},
+	})
+
+	l := ech0.New(w, "svc")
+	l.SetLevel(gommon.DEBUG)
+	l.Warn("m1")
+
+	z := zerolog.New(w)
+	z.Error().
+		Str("trace_id", "0af7651916cd43dd8448eb211c80319c").
+		Str("span_id", "b7ad6b7169203331").
+		Int("n", 3).Float64("f", 1.5).Bool("b", true).
+		Strs("ss", []string{"x"}).
+		Dict("d", zerolog.Dict().Str("k", "v")).
+		Msg("m2")
+	z.Log().Str("trace_id", "not-hex").Send()
+
+	g.Expect(w.Close()).To(Succeed())
+	g.Expect(path).To(Equal("/v1/logs"))
+
+	var req map[string]interface{}
+	g.Expect(json.Unmarshal(body, &req)).To(Succeed())
+
+	rl := req["resourceLogs"].([]interface{})[0].(map[string]interface{})
+	g.Expect(rl["resource"]).To(Equal(map[string]interface{}{"attributes": []interface{}{
+		map[string]interface{}{"key": "service.name", "value": map[string]interface{}{"stringValue": "orders"}},
+	}}))
+
+	sl := rl["scopeLogs"].([]interface{})[0].(map[string]interface{})
+	g.Expect(sl["scope"]).To(Equal(map[string]interface{}{"name": DefaultScopeName}))
+
+	records := sl["logRecords"].([]interface{})
+	g.Expect(records).To(HaveLen(3))
+
+	r0 := records[0].(map[string]interface{})
+	g.Expect(r0["severityNumber"]).To(Equal(13.0))
+	g.Expect(r0["severityText"]).To(Equal("WARN"))
+	g.Expect(r0["body"]).To(Equal(map[string]interface{}{"stringValue": "m1"}))
+	g.Expect(r0["timeUnixNano"]).NotTo(BeEmpty())
+	g.Expect(r0["attributes"]).To(Equal([]interface{}{
+		map[string]interface{}{"key": "prefix", "value": map[string]interface{}{"stringValue": "svc"}},
+	}))
+
+	r1 := records[1].(map[string]interface{})
+	g.Expect(r1["severityNumber"]).To(Equal(17.0))
+	g.Expect(r1["traceId"]).To(Equal("0af7651916cd43dd8448eb211c80319c"))
+	g.Expect(r1["spanId"]).To(Equal("b7ad6b7169203331"))
+	g.Expect(r1["attributes"]).To(Equal([]interface{}{
+		map[string]interface{}{"key": "b", "value": map[string]interface{}{"boolValue": true}},
+		map[string]interface{}{"key": "d", "value": map[string]interface{}{"kvlistValue": map[string]interface{}{"values": []interface{}{
+			map[string]interface{}{"key": "k", "value": map[string]interface{}{"stringValue": "v"}},
+		}}}},
+		map[string]interface{}{"key": "f", "value": map[string]interface{}{"doubleValue": 1.5}},
+		map[string]interface{}{"key": "n", "value": map[string]interface{}{"intValue": "3"}},
+		map[string]interface{}{"key": "ss", "value": map[string]interface{}{"arrayValue": map[string]interface{}{"values": []interface{}{
+			map[string]interface{}{"stringValue": "x"},
+		}}}},
+	}))
+
+	r2 := records[2].(map[string]interface{})
+	g.Expect(r2).NotTo(HaveKey("severityNumber"))
+	g.Expect(r2).NotTo(HaveKey("traceId"))
+	g.Expect(r2).NotTo(HaveKey("body"))
+	g.Expect(r2["attributes"]).To(HaveLen(1))
+}
+
+func TestPartialSuccess(t *testing.T) {
+	g := NewGomegaWithT(t)
+	f := NewFormat(Options{})
+
+	g.Expect(f.Check([]byte(`{}`))).To(Succeed())
+	g.Expect(f.Check([]byte(`{"partialSuccess":{"rejectedLogRecords":"2","errorMessage":"too old"}}`))).
+		To(MatchError("otlp: 2 log records rejected: too old"))
+}
@@ -0,0 +1,108 @@
+package ech0
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+)
+
+// StackMarshaler extracts diagnostic information, such as a stack trace, from an error.
+// It is used by ZeroEvent.Err after ZeroEvent.Stack has been called; the result is logged
+// with the zerolog.ErrorStackFieldName key. Each logger has its own StackMarshaler
+// (see Zero.ErrorStack), so the global zerolog.ErrorStackMarshaler is not needed.
+type StackMarshaler func(err error) interface{}
+
+// StackTracer is implemented by errors that record the stack where they were created,
+// as a list of program counters such as is returned by runtime.Callers.
+type StackTracer interface {
+	Callers() []uintptr
+}
+
+// ErrorFrame describes one error in a chain of wrapped errors.
+type ErrorFrame struct {
+	Message string   `json:"message"`
+	Type    string   `json:"type"`
+	Stack   []string `json:"stack,omitempty"`
+}
+
+// maxChain guards against errors that unwrap to themselves.
+const maxChain = 100
+
+// ErrorChain is the built-in StackMarshaler. It follows the chain of wrapped errors
+// (see errors.Unwrap), recording the message and type of each as an ErrorFrame.
+// Errors that wrap several errors are followed depth-first. For errors that implement
+// StackTracer, the stack trace is included too.
+func ErrorChain(err error) interface{} {
+	var chain []ErrorFrame
+	return appendChain(chain, err)
+}
+
+// marshalStack uses m to marshal err, or ErrorChain if m is nil.
+func marshalStack(m StackMarshaler, err error) interface{} {
+	if m == nil {
+		return ErrorChain(err)
+	}
+	return m(err)
+}
+
+// noStack is used by Zero.ErrorStack(nil) so that no stack is logged.
+func noStack(error) interface{} {
+	return nil
+}
+
+func appendChain(chain []ErrorFrame, err error) []ErrorFrame {
+	for err != nil && len(chain) < maxChain {
+		frame := ErrorFrame{Message: err.Error(), Type: fmt.Sprintf("%T", err)}
+		if st, ok := err.(StackTracer); ok {
+			frame.Stack = formatStack(st.Callers())
+		}
+		chain = append(chain, frame)
+
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, e := range x.Unwrap() {
+				chain = appendChain(chain, e)
+			}
+			return chain
+		default:
+			return chain
+		}
+	}
+	return chain
+}
+
+func formatStack(pcs []uintptr) []string {
+	var stack []string
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		stack = append(stack, f.Function+" "+f.File+":"+strconv.Itoa(f.Line))
+		if !more {
+			return stack
+		}
+	}
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// WithStack wraps err so that it records the stack of the caller. The stack is
+// logged by ErrorChain. If err is nil, WithStack returns nil.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return &stackError{err: err, pcs: pcs[:n]}
+}
+
+type stackError struct {
+	err error
+	pcs []uintptr
+}
+
+func (e *stackError) Error() string      { return e.err.Error() }
+func (e *stackError) Unwrap() error      { return e.err }
+func (e *stackError) Callers() []uintptr { return e.pcs }
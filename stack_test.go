@@ -0,0 +1,96 @@
+package ech0
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+func TestStack_errorChain(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	z := Wrap(zerolog.New(buf))
+
+	err := fmt.Errorf("outer: %w", WithStack(errors.New("inner")))
+	z.Error().Stack().Err(err).Send()
+
+	var m struct {
+		Stack []ErrorFrame `json:"stack"`
+	}
+	g.Expect(json.Unmarshal([]byte(buf.String()), &m)).To(Succeed())
+	g.Expect(m.Stack).To(HaveLen(3))
+	g.Expect(m.Stack[0]).To(Equal(ErrorFrame{Message: "outer: inner", Type: "*fmt.wrapError"}))
+	g.Expect(m.Stack[1].Type).To(Equal("*ech0.stackError"))
+	g.Expect(m.Stack[1].Stack[0]).To(HavePrefix("github.com/rickb777/ech0/v3.TestStack_errorChain "))
+	g.Expect(m.Stack[2]).To(Equal(ErrorFrame{Message: "inner", Type: "*errors.errorString"}))
+}
+
+func TestStack_perLogger(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	z := Wrap(zerolog.New(buf))
+
+	z.ErrorStack(nil).Error().Stack().Err(e1).Send()
+	g.Expect(buf.String()).To(Equal(`{"level":"error","error":"x1"`+newline), buf.String())
+
+	buf.Reset()
+	z.ErrorStack(func(err error) interface{} { return "s" }).Error().Stack().Err(e1).Send()
+	g.Expect(buf.String()).To(Equal(`{"level":"error","error":"x1","stack":"s"`+newline), buf.String())
+
+	buf.Reset()
+	z.Error().Err(e1).Send()
+	g.Expect(buf.String()).To(Equal(`{"level":"error","error":"x1"`+newline), buf.String())
+}
+
+func TestStack_context(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	z := Wrap(zerolog.New(buf)).ErrorStack(func(err error) interface{} { return "s" })
+
+	child := z.With().Stack().Str("a", "b").Logger()
+	child.Error().Err(e1).Send()
+	g.Expect(buf.String()).To(Equal(`{"level":"error","a":"b","error":"x1","stack":"s"`+newline), buf.String())
+
+	buf.Reset()
+	child.Err(e1).Send()
+	g.Expect(buf.String()).To(Equal(`{"level":"error","a":"b","error":"x1","stack":"s"`+newline), buf.String())
+
+	buf.Reset()
+	child.Level(zerolog.InfoLevel).Int("n", 1).Warn().Err(e1).Send()
+	g.Expect(buf.String()).To(Equal(`{"level":"warn","a":"b","n":1,"error":"x1","stack":"s"`+newline), buf.String())
+
+	buf.Reset()
+	z.Error().Err(e1).Send()
+	g.Expect(buf.String()).To(Equal(`{"level":"error","error":"x1"`+newline), buf.String())
+}
+
+// objectFunc is an ObjectMarshaler implemented by a function.
+type objectFunc func(e ZeroEvent)
+
+func (f objectFunc) MarshalZeroObject(e ZeroEvent) {
+	f(e)
+}
+
+func TestStack_object(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	z := Wrap(zerolog.New(buf)).ErrorStack(func(err error) interface{} { return "s" })
+
+	z.Error().Object("o", objectFunc(func(e ZeroEvent) { e.Stack().Err(e1) })).Send()
+	g.Expect(buf.String()).To(Equal(`{"level":"error","o":{"error":"x1","stack":"s"}`+newline), buf.String())
+}
+
+func TestCaller(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	z := Wrap(zerolog.New(buf))
+
+	z.Info().Caller().Send()
+	g.Expect(buf.String()).To(ContainSubstring(`"caller":`))
+	g.Expect(buf.String()).To(ContainSubstring(`stack_test.go:`))
+}
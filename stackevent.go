@@ -0,0 +1,408 @@
+package ech0
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+var _ ZeroEvent = &stackEvent{}
+
+// stackEvent wraps a zerolog.Event along with the stack settings of the logger that
+// created it. It is only used when the settings differ from the defaults, or when Stack
+// is called; other events are zeroEvents, which cost nothing to create.
+type stackEvent struct {
+	e         *zerolog.Event
+	stack     StackMarshaler // nil for the default, ErrorChain
+	withStack bool
+}
+
+// Send is equivalent to calling Msg("").
+//
+// NOTICE: once this method is called, the ZeroEvent should be disposed.
+func (se *stackEvent) Send() {
+	se.e.Send()
+}
+
+// Msg sends the ZeroEvent with msg added as the message field if not empty.
+//
+// NOTICE: once this method is called, the *Event should be disposed.
+// Calling Msg twice can have unexpected result.
+func (se *stackEvent) Msg(s string) {
+	se.e.Msg(s)
+}
+
+// Msgf sends the event with formatted msg added as the message field if not empty.
+//
+// NOTICE: once this method is called, the ZeroEvent should be disposed.
+// Calling Msgf twice can have unexpected result.
+func (se *stackEvent) Msgf(format string, v ...interface{}) {
+	se.e.Msgf(format, v...)
+}
+
+// Enabled return false if the *Event is going to be filtered out by
+// log level or sampling.
+func (se *stackEvent) Enabled() bool {
+	return se.e.Enabled()
+}
+
+// Discard disables the event so Msg(f) won't print it.
+func (se *stackEvent) Discard() ZeroEvent {
+	se.e = se.e.Discard()
+	return se
+}
+
+// Func allows an anonymous func to run only if the event is enabled. This
+// avoids computing fields that would not be logged.
+func (se *stackEvent) Func(f func(e ZeroEvent)) ZeroEvent {
+	if se.Enabled() {
+		f(se)
+	}
+	return se
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// AnErr adds the field key with serialized err to the ZeroEvent context.
+// If err is nil, no field is added.
+func (se *stackEvent) AnErr(key string, err error) ZeroEvent {
+	se.e = se.e.AnErr(key, err)
+	return se
+}
+
+// Bool adds the field key with val as a bool to the ZeroEvent context.
+func (se *stackEvent) Bool(key string, val bool) ZeroEvent {
+	se.e = se.e.Bool(key, val)
+	return se
+}
+
+// Bools adds the field key with val as a []bool to the ZeroEvent context.
+func (se *stackEvent) Bools(key string, b []bool) ZeroEvent {
+	se.e = se.e.Bools(key, b)
+	return se
+}
+
+// Bytes adds the field key with val as a string to the ZeroEvent context.
+//
+// Runes outside of normal ASCII ranges will be hex-encoded in the resulting
+// JSON.
+func (se *stackEvent) Bytes(key string, val []byte) ZeroEvent {
+	se.e = se.e.Bytes(key, val)
+	return se
+}
+
+// Dict adds the field key with a dict to the event context; see zeroEvent.Dict.
+func (se *stackEvent) Dict(key string, dict ZeroEvent) ZeroEvent {
+	switch d := unwrapDict(dict).(type) {
+	case *zeroEvent, *stackEvent, disabledEvent, nil:
+		// not a replayable dictionary
+	case ObjectMarshaler:
+		se.e = se.e.Object(key, objectMarshaler{obj: d, stack: se.stack})
+	default:
+		se.e = se.e.Str(key, fmt.Sprint(dict))
+	}
+	return se
+}
+
+// Object marshals an object that implements the ObjectMarshaler interface.
+func (se *stackEvent) Object(key string, obj ObjectMarshaler) ZeroEvent {
+	se.e = se.e.Object(key, objectMarshaler{obj: obj, stack: se.stack})
+	return se
+}
+
+// EmbedObject marshals an object that implements the ObjectMarshaler interface,
+// adding its fields directly to the ZeroEvent context.
+func (se *stackEvent) EmbedObject(obj ObjectMarshaler) ZeroEvent {
+	se.e = se.e.EmbedObject(objectMarshaler{obj: obj, stack: se.stack})
+	return se
+}
+
+// Array adds the field key with an array to the ZeroEvent context.
+// Use Arr() to create the array or pass a type that
+// implements the ArrayMarshaler interface.
+func (se *stackEvent) Array(key string, arr ArrayMarshaler) ZeroEvent {
+	se.e = se.e.Array(key, arrayMarshaler{arr})
+	return se
+}
+
+// Dur adds the field key with duration d stored as zerolog.DurationFieldUnit.
+// If zerolog.DurationFieldInteger is true, durations are rendered as integer
+// instead of float.
+func (se *stackEvent) Dur(key string, val time.Duration) ZeroEvent {
+	se.e = se.e.Dur(key, val)
+	return se
+}
+
+// Err adds the field "error" with serialized err to the ZeroEvent context.
+// If err is nil, no field is added.
+//
+// To customize the key name, change zerolog.ErrorFieldName.
+//
+// If Stack() has been called before, the err is passed to the logger's StackMarshaler
+// (see Zero.ErrorStack) and the result is appended to the zerolog.ErrorStackFieldName.
+// The global zerolog.ErrorStackMarshaler is not used.
+func (se *stackEvent) Err(err error) ZeroEvent {
+	se.e = se.e.Err(err)
+	if err != nil && se.withStack && se.e.Enabled() {
+		if stack := marshalStack(se.stack, err); stack != nil {
+			se.e = se.e.Interface(zerolog.ErrorStackFieldName, stack)
+		}
+	}
+	return se
+}
+
+// Stack enables stack trace printing for the error passed to Err().
+func (se *stackEvent) Stack() ZeroEvent {
+	se.withStack = true
+	return se
+}
+
+// Caller adds the file:line of the caller with the zerolog.CallerFieldName key.
+// The argument skip is the number of additional stack frames to ascend;
+// zerolog.CallerSkipFrameCount is always applied.
+func (se *stackEvent) Caller(skip ...int) ZeroEvent {
+	sk := 1 // skip this method
+	if len(skip) > 0 {
+		sk += skip[0]
+	}
+	se.e = se.e.Caller(sk)
+	return se
+}
+
+// Hex adds the field key with val as a hex string to the ZeroEvent context.
+func (se *stackEvent) Hex(key string, val []byte) ZeroEvent {
+	se.e = se.e.Hex(key, val)
+	return se
+}
+
+// Int adds the field key with i as a int to the ZeroEvent context.
+func (se *stackEvent) Int(key string, val int) ZeroEvent {
+	se.e = se.e.Int(key, val)
+	return se
+}
+
+// Ints adds the field key with i as a int to the ZeroEvent context.
+func (se *stackEvent) Ints(key string, val []int) ZeroEvent {
+	se.e = se.e.Ints(key, val)
+	return se
+}
+
+// Int64 adds the field key with i as a int64 to the ZeroEvent context.
+func (se *stackEvent) Int64(key string, val int64) ZeroEvent {
+	se.e = se.e.Int64(key, val)
+	return se
+}
+
+// Interface adds the field key with i marshaled using reflection.
+func (se *stackEvent) Interface(key string, val interface{}) ZeroEvent {
+	se.e = se.e.Interface(key, val)
+	return se
+}
+
+// Str adds the field key with val as a string to the ZeroEvent context.
+func (se *stackEvent) Str(key string, val string) ZeroEvent {
+	se.e = se.e.Str(key, val)
+	return se
+}
+
+// Strs adds the field key with vals as a []string to the ZeroEvent context.
+func (se *stackEvent) Strs(key string, val []string) ZeroEvent {
+	se.e = se.e.Strs(key, val)
+	return se
+}
+
+// Stringer adds the field key with val.String() (or null if val is nil) to the ZeroEvent context.
+func (se *stackEvent) Stringer(key string, val fmt.Stringer) ZeroEvent {
+	se.e = se.e.Stringer(key, val)
+	return se
+}
+
+// Time adds the field key with t formated as string using zerolog.TimeFieldFormat.
+func (se *stackEvent) Time(key string, val time.Time) ZeroEvent {
+	se.e = se.e.Time(key, val)
+	return se
+}
+
+// Timestamp adds the current local time as UNIX timestamp to the *Event context with the "time" key.
+// To customize the key name, change zerolog.TimestampFieldName.
+//
+// NOTE: It won't dedupe the "time" key if the *Event (or *Context) has one
+// already.
+func (se *stackEvent) Timestamp() ZeroEvent {
+	se.e = se.e.Timestamp()
+	return se
+}
+
+// Uint adds the field key with i as a uint to the ZeroEvent context.
+func (se *stackEvent) Uint(key string, val uint) ZeroEvent {
+	se.e = se.e.Uint(key, val)
+	return se
+}
+
+// Uint adds the field key with i as a uint to the ZeroEvent context.
+func (se *stackEvent) Uints(key string, val []uint) ZeroEvent {
+	se.e = se.e.Uints(key, val)
+	return se
+}
+
+// Uint64 adds the field key with i as a uint to the ZeroEvent context.
+func (se *stackEvent) Uint64(key string, val uint64) ZeroEvent {
+	se.e = se.e.Uint64(key, val)
+	return se
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// Errs adds the field key with errs as an array of serialized errors to the ZeroEvent context.
+func (se *stackEvent) Errs(key string, errs []error) ZeroEvent {
+	se.e = se.e.Errs(key, errs)
+	return se
+}
+
+// Float32 adds the field key with val as a float32 to the ZeroEvent context.
+func (se *stackEvent) Float32(key string, val float32) ZeroEvent {
+	se.e = se.e.Float32(key, val)
+	return se
+}
+
+// Floats32 adds the field key with val as a []float32 to the ZeroEvent context.
+func (se *stackEvent) Floats32(key string, val []float32) ZeroEvent {
+	se.e = se.e.Floats32(key, val)
+	return se
+}
+
+// Float64 adds the field key with val as a float64 to the ZeroEvent context.
+func (se *stackEvent) Float64(key string, val float64) ZeroEvent {
+	se.e = se.e.Float64(key, val)
+	return se
+}
+
+// Floats64 adds the field key with val as a []float64 to the ZeroEvent context.
+func (se *stackEvent) Floats64(key string, val []float64) ZeroEvent {
+	se.e = se.e.Floats64(key, val)
+	return se
+}
+
+// Int8 adds the field key with val as a int8 to the ZeroEvent context.
+func (se *stackEvent) Int8(key string, val int8) ZeroEvent {
+	se.e = se.e.Int8(key, val)
+	return se
+}
+
+// Int16 adds the field key with val as a int16 to the ZeroEvent context.
+func (se *stackEvent) Int16(key string, val int16) ZeroEvent {
+	se.e = se.e.Int16(key, val)
+	return se
+}
+
+// Int32 adds the field key with val as a int32 to the ZeroEvent context.
+func (se *stackEvent) Int32(key string, val int32) ZeroEvent {
+	se.e = se.e.Int32(key, val)
+	return se
+}
+
+// Ints8 adds the field key with val as a []int8 to the ZeroEvent context.
+func (se *stackEvent) Ints8(key string, val []int8) ZeroEvent {
+	se.e = se.e.Ints8(key, val)
+	return se
+}
+
+// Ints16 adds the field key with val as a []int16 to the ZeroEvent context.
+func (se *stackEvent) Ints16(key string, val []int16) ZeroEvent {
+	se.e = se.e.Ints16(key, val)
+	return se
+}
+
+// Ints32 adds the field key with val as a []int32 to the ZeroEvent context.
+func (se *stackEvent) Ints32(key string, val []int32) ZeroEvent {
+	se.e = se.e.Ints32(key, val)
+	return se
+}
+
+// Ints64 adds the field key with val as a []int64 to the ZeroEvent context.
+func (se *stackEvent) Ints64(key string, val []int64) ZeroEvent {
+	se.e = se.e.Ints64(key, val)
+	return se
+}
+
+// Uint8 adds the field key with val as a uint8 to the ZeroEvent context.
+func (se *stackEvent) Uint8(key string, val uint8) ZeroEvent {
+	se.e = se.e.Uint8(key, val)
+	return se
+}
+
+// Uint16 adds the field key with val as a uint16 to the ZeroEvent context.
+func (se *stackEvent) Uint16(key string, val uint16) ZeroEvent {
+	se.e = se.e.Uint16(key, val)
+	return se
+}
+
+// Uint32 adds the field key with val as a uint32 to the ZeroEvent context.
+func (se *stackEvent) Uint32(key string, val uint32) ZeroEvent {
+	se.e = se.e.Uint32(key, val)
+	return se
+}
+
+// Uints8 adds the field key with val as a []uint8 to the ZeroEvent context.
+func (se *stackEvent) Uints8(key string, val []uint8) ZeroEvent {
+	se.e = se.e.Uints8(key, val)
+	return se
+}
+
+// Uints16 adds the field key with val as a []uint16 to the ZeroEvent context.
+func (se *stackEvent) Uints16(key string, val []uint16) ZeroEvent {
+	se.e = se.e.Uints16(key, val)
+	return se
+}
+
+// Uints32 adds the field key with val as a []uint32 to the ZeroEvent context.
+func (se *stackEvent) Uints32(key string, val []uint32) ZeroEvent {
+	se.e = se.e.Uints32(key, val)
+	return se
+}
+
+// Uints64 adds the field key with val as a []uint64 to the ZeroEvent context.
+func (se *stackEvent) Uints64(key string, val []uint64) ZeroEvent {
+	se.e = se.e.Uints64(key, val)
+	return se
+}
+
+// Times adds the field key with val formated as string using zerolog.TimeFieldFormat.
+func (se *stackEvent) Times(key string, val []time.Time) ZeroEvent {
+	se.e = se.e.Times(key, val)
+	return se
+}
+
+// Durs adds the field key with val stored as zerolog.DurationFieldUnit.
+func (se *stackEvent) Durs(key string, val []time.Duration) ZeroEvent {
+	se.e = se.e.Durs(key, val)
+	return se
+}
+
+// TimeDiff adds the field key with positive duration between time t and start.
+// If time t is not greater than start, duration will be 0.
+// Duration format follows the same principle as Dur().
+func (se *stackEvent) TimeDiff(key string, t time.Time, start time.Time) ZeroEvent {
+	se.e = se.e.TimeDiff(key, t, start)
+	return se
+}
+
+// IPAddr adds IPv4 or IPv6 Address to the ZeroEvent context.
+func (se *stackEvent) IPAddr(key string, ip net.IP) ZeroEvent {
+	se.e = se.e.IPAddr(key, ip)
+	return se
+}
+
+// IPPrefix adds IPv4 or IPv6 Prefix (address and mask) to the ZeroEvent context.
+func (se *stackEvent) IPPrefix(key string, pfx net.IPNet) ZeroEvent {
+	se.e = se.e.IPPrefix(key, pfx)
+	return se
+}
+
+// MACAddr adds MAC address to the ZeroEvent context.
+func (se *stackEvent) MACAddr(key string, ha net.HardwareAddr) ZeroEvent {
+	se.e = se.e.MACAddr(key, ha)
+	return se
+}
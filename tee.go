@@ -0,0 +1,868 @@
+package ech0
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// teeFrames is the number of stack frames that a teeEvent adds between the
+// caller and the underlying event's Msg or Send.
+const teeFrames = 3
+
+type teeLogger struct {
+	zs   []Zero
+	exit func(code int) // called after a Fatal event; nil for os.Exit
+	out  io.Writer      // set by Output; flushed before exiting
+}
+
+var (
+	_ Zero       = teeLogger{}
+	_ Terminator = teeLogger{}
+)
+
+// Tee returns a logger that sends every event to all of zs. Each of them applies its own
+// level, so an event may be written by some and not others. This is useful for writing
+// to a production sink and a test logger at the same time, or for migrating between sinks.
+//
+// Child loggers created by Str, Int, Level, Output etc apply to all of zs.
+//
+// For Panic events, every logger receives the event before the panic. For Fatal events,
+// every logger receives the event before the program exits, even if none of them accepts
+// fatal events. Then the output set by Output is flushed and the program exits using the
+// first logger that implements Terminator, such as a testlogger.TestLogger, or os.Exit if
+// there is none.
+func Tee(zs ...Zero) Zero {
+	switch len(zs) {
+	case 0:
+		return Nop()
+	case 1:
+		return zs[0]
+	}
+
+	t := teeLogger{zs: append([]Zero(nil), zs...)}
+	for _, z := range zs {
+		if term, ok := z.(Terminator); ok {
+			t.exit = term.Terminate
+			break
+		}
+	}
+	return t
+}
+
+func (t teeLogger) event(fn func(Zero) ZeroEvent) ZeroEvent {
+	events := t.events(fn)
+	if len(events) == 0 {
+		return disabledEvent{}
+	}
+	return &teeEvent{events: events}
+}
+
+func (t teeLogger) events(fn func(Zero) ZeroEvent) []ZeroEvent {
+	var events []ZeroEvent
+	for _, z := range t.zs {
+		if e := fn(z); e.Enabled() {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+func (t teeLogger) child(fn func(Zero) Zero) Zero {
+	c := t
+	c.zs = make([]Zero, len(t.zs))
+	for i, z := range t.zs {
+		c.zs[i] = fn(z)
+	}
+	return c
+}
+
+// Terminate flushes the output set by Output, if any, then ends the program; see Tee.
+func (t teeLogger) Terminate(code int) {
+	if t.out != nil {
+		Flush(t.out)
+	}
+	exit(t.exit, code)
+}
+
+// Log starts a new message with no level.
+func (t teeLogger) Log() ZeroEvent {
+	return t.event(func(z Zero) ZeroEvent { return z.Log() })
+}
+
+// Debug starts a new message with debug level.
+func (t teeLogger) Debug() ZeroEvent {
+	return t.event(func(z Zero) ZeroEvent { return z.Debug() })
+}
+
+// Info starts a new message with info level.
+func (t teeLogger) Info() ZeroEvent {
+	return t.event(func(z Zero) ZeroEvent { return z.Info() })
+}
+
+// Warn starts a new message with warn level.
+func (t teeLogger) Warn() ZeroEvent {
+	return t.event(func(z Zero) ZeroEvent { return z.Warn() })
+}
+
+// Error starts a new message with error level.
+func (t teeLogger) Error() ZeroEvent {
+	return t.event(func(z Zero) ZeroEvent { return z.Error() })
+}
+
+// Fatal starts a new message with fatal level. The Msg method exits the program after
+// all the loggers have received the event; see Tee.
+func (t teeLogger) Fatal() ZeroEvent {
+	events := t.events(func(z Zero) ZeroEvent { return z.WithLevel(zerolog.FatalLevel) })
+	return &teeEvent{events: events, terminate: func() { t.Terminate(1) }}
+}
+
+// Panic starts a new message with panic level. The panic() function
+// is called by the Msg method, after all the loggers have received the event.
+func (t teeLogger) Panic() ZeroEvent {
+	return t.event(func(z Zero) ZeroEvent { return z.Panic() })
+}
+
+// Err starts a new message with error level with err as a field if not nil or
+// with info level if err is nil.
+func (t teeLogger) Err(err error) ZeroEvent {
+	return t.event(func(z Zero) ZeroEvent { return z.Err(err) })
+}
+
+// WithLevel starts a new message with level.
+func (t teeLogger) WithLevel(level zerolog.Level) ZeroEvent {
+	return t.event(func(z Zero) ZeroEvent { return z.WithLevel(level) })
+}
+
+// Output duplicates the current loggers and sets w as their output.
+func (t teeLogger) Output(w io.Writer) Zero {
+	c := t.child(func(z Zero) Zero { return z.Output(w) }).(teeLogger)
+	c.out = w
+	return c
+}
+
+// ErrorStack creates child loggers that use m to marshal errors.
+func (t teeLogger) ErrorStack(m StackMarshaler) Zero {
+	return t.child(func(z Zero) Zero { return z.ErrorStack(m) })
+}
+
+// Level creates child loggers with the minimum accepted level set to level.
+func (t teeLogger) Level(lvl zerolog.Level) Zero {
+	return t.child(func(z Zero) Zero { return z.Level(lvl) })
+}
+
+// Str creates child loggers with the field key and with val as a string.
+func (t teeLogger) Str(key, val string) Zero {
+	return t.child(func(z Zero) Zero { return z.Str(key, val) })
+}
+
+// Int creates child loggers with the field key and with val as an int.
+func (t teeLogger) Int(key string, val int) Zero {
+	return t.child(func(z Zero) Zero { return z.Int(key, val) })
+}
+
+// Bool creates child loggers with the field key and with val as a bool.
+func (t teeLogger) Bool(key string, val bool) Zero {
+	return t.child(func(z Zero) Zero { return z.Bool(key, val) })
+}
+
+// RawJSON creates child loggers with the field key with val as already encoded JSON.
+func (t teeLogger) RawJSON(key string, b []byte) Zero {
+	return t.child(func(z Zero) Zero { return z.RawJSON(key, b) })
+}
+
+// Timestamp creates child loggers that add the current time to each event.
+func (t teeLogger) Timestamp() Zero {
+	return t.child(func(z Zero) Zero { return z.Timestamp() })
+}
+
+// With creates a child logger builder, to which any fields can be added.
+func (t teeLogger) With() ZeroContext {
+	tc := teeContext{cs: make([]ZeroContext, len(t.zs)), parent: t}
+	for i, z := range t.zs {
+		tc.cs[i] = z.With()
+	}
+	return tc
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// teeEvent sends each field and the message to several events.
+type teeEvent struct {
+	events    []ZeroEvent
+	terminate func() // only for fatal events
+}
+
+var (
+	_ ZeroEvent       = &teeEvent{}
+	_ ObjectMarshaler = &teeEvent{}
+)
+
+// Send is equivalent to calling Msg("").
+func (te *teeEvent) Send() {
+	te.deliver("", true)
+}
+
+// Msg sends the event to every logger, with msg added as the message field if not empty.
+func (te *teeEvent) Msg(msg string) {
+	te.deliver(msg, false)
+}
+
+// Msgf sends the event to every logger, with formatted msg added as the message field if not empty.
+func (te *teeEvent) Msgf(format string, v ...interface{}) {
+	te.deliver(fmt.Sprintf(format, v...), false)
+}
+
+// deliver sends the event to every logger. If any of them panics, the first panic
+// is repeated after all the loggers have received the event.
+func (te *teeEvent) deliver(msg string, send bool) {
+	var first interface{}
+	for _, e := range te.events {
+		if p := deliverOne(e, msg, send); p != nil && first == nil {
+			first = p
+		}
+	}
+
+	if first != nil {
+		panic(first)
+	}
+	if te.terminate != nil {
+		te.terminate()
+	}
+}
+
+func deliverOne(e ZeroEvent, msg string, send bool) (p interface{}) {
+	defer func() {
+		p = recover()
+	}()
+
+	if send {
+		e.Send()
+	} else {
+		e.Msg(msg)
+	}
+	return nil
+}
+
+// Enabled returns true if any of the events is enabled.
+func (te *teeEvent) Enabled() bool {
+	for _, e := range te.events {
+		if e.Enabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// Discard disables all the events.
+func (te *teeEvent) Discard() ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Discard()
+	}
+	return te
+}
+
+// MarshalZeroObject replays the fields of the first event that implements ObjectMarshaler,
+// such as a testlogger event, so that the Tee event can be used as a dictionary.
+func (te *teeEvent) MarshalZeroObject(e ZeroEvent) {
+	if obj, ok := unwrapDict(te).(ObjectMarshaler); ok {
+		obj.MarshalZeroObject(e)
+	}
+}
+
+// Func allows an anonymous func to run only if the event is enabled. The func
+// runs once; the fields it adds are sent to every event.
+func (te *teeEvent) Func(f func(e ZeroEvent)) ZeroEvent {
+	if te.Enabled() {
+		f(te)
+	}
+	return te
+}
+
+// Caller adds the file:line of the caller with the zerolog.CallerFieldName key.
+func (te *teeEvent) Caller(skip ...int) ZeroEvent {
+	sk := 1 // skip this method
+	if len(skip) > 0 {
+		sk += skip[0]
+	}
+	for i, e := range te.events {
+		te.events[i] = e.Caller(sk)
+	}
+	return te
+}
+
+func (te *teeEvent) Stack() ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Stack()
+	}
+	return te
+}
+
+func (te *teeEvent) AnErr(key string, val error) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.AnErr(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Bool(key string, val bool) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Bool(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Bools(key string, b []bool) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Bools(key, b)
+	}
+	return te
+}
+
+func (te *teeEvent) Bytes(key string, val []byte) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Bytes(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Dict(key string, dict ZeroEvent) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Dict(key, dict)
+	}
+	return te
+}
+
+func (te *teeEvent) Object(key string, obj ObjectMarshaler) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Object(key, obj)
+	}
+	return te
+}
+
+func (te *teeEvent) EmbedObject(obj ObjectMarshaler) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.EmbedObject(obj)
+	}
+	return te
+}
+
+func (te *teeEvent) Array(key string, arr ArrayMarshaler) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Array(key, arr)
+	}
+	return te
+}
+
+func (te *teeEvent) Dur(key string, val time.Duration) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Dur(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Err(err error) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Err(err)
+	}
+	return te
+}
+
+func (te *teeEvent) Hex(key string, val []byte) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Hex(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Int(key string, val int) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Int(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Ints(key string, val []int) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Ints(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Int64(key string, val int64) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Int64(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Interface(key string, val interface{}) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Interface(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Str(key, val string) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Str(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Strs(key string, val []string) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Strs(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Stringer(key string, val fmt.Stringer) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Stringer(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Time(key string, val time.Time) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Time(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Timestamp() ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Timestamp()
+	}
+	return te
+}
+
+func (te *teeEvent) Uint(key string, val uint) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Uint(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Uints(key string, val []uint) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Uints(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Uint64(key string, val uint64) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Uint64(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Errs(key string, errs []error) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Errs(key, errs)
+	}
+	return te
+}
+
+func (te *teeEvent) Float32(key string, val float32) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Float32(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Floats32(key string, val []float32) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Floats32(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Float64(key string, val float64) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Float64(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Floats64(key string, val []float64) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Floats64(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Int8(key string, val int8) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Int8(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Int16(key string, val int16) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Int16(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Int32(key string, val int32) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Int32(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Ints8(key string, val []int8) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Ints8(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Ints16(key string, val []int16) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Ints16(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Ints32(key string, val []int32) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Ints32(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Ints64(key string, val []int64) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Ints64(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Uint8(key string, val uint8) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Uint8(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Uint16(key string, val uint16) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Uint16(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Uint32(key string, val uint32) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Uint32(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Uints8(key string, val []uint8) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Uints8(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Uints16(key string, val []uint16) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Uints16(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Uints32(key string, val []uint32) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Uints32(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Uints64(key string, val []uint64) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Uints64(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Times(key string, val []time.Time) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Times(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) Durs(key string, val []time.Duration) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.Durs(key, val)
+	}
+	return te
+}
+
+func (te *teeEvent) TimeDiff(key string, t time.Time, start time.Time) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.TimeDiff(key, t, start)
+	}
+	return te
+}
+
+func (te *teeEvent) IPAddr(key string, ip net.IP) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.IPAddr(key, ip)
+	}
+	return te
+}
+
+func (te *teeEvent) IPPrefix(key string, pfx net.IPNet) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.IPPrefix(key, pfx)
+	}
+	return te
+}
+
+func (te *teeEvent) MACAddr(key string, ha net.HardwareAddr) ZeroEvent {
+	for i, e := range te.events {
+		te.events[i] = e.MACAddr(key, ha)
+	}
+	return te
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// teeContext builds child loggers for all the branches of a Tee.
+type teeContext struct {
+	cs     []ZeroContext
+	parent teeLogger
+}
+
+var _ ZeroContext = teeContext{}
+
+// Logger returns the Tee of the child loggers.
+func (tc teeContext) Logger() Zero {
+	t := tc.parent
+	t.zs = make([]Zero, len(tc.cs))
+	for i, c := range tc.cs {
+		t.zs[i] = c.Logger()
+	}
+	return t
+}
+
+func (tc teeContext) with(fn func(ZeroContext) ZeroContext) ZeroContext {
+	out := teeContext{cs: make([]ZeroContext, len(tc.cs)), parent: tc.parent}
+	for i, c := range tc.cs {
+		out.cs[i] = fn(c)
+	}
+	return out
+}
+
+// Caller adds the file:line of the caller with the zerolog.CallerFieldName key.
+func (tc teeContext) Caller() ZeroContext {
+	return tc.CallerWithSkipFrameCount(-1)
+}
+
+// CallerWithSkipFrameCount adds the file:line of the caller with the zerolog.CallerFieldName key.
+// If skipFrameCount is -1, the global zerolog.CallerSkipFrameCount is used.
+func (tc teeContext) CallerWithSkipFrameCount(skipFrameCount int) ZeroContext {
+	if skipFrameCount < 0 {
+		skipFrameCount = zerolog.CallerSkipFrameCount
+	}
+	return tc.with(func(c ZeroContext) ZeroContext { return c.CallerWithSkipFrameCount(skipFrameCount + teeFrames) })
+}
+
+func (tc teeContext) Str(key, val string) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Str(key, val) })
+}
+
+func (tc teeContext) Strs(key string, vals []string) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Strs(key, vals) })
+}
+
+func (tc teeContext) Stringer(key string, val fmt.Stringer) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Stringer(key, val) })
+}
+
+func (tc teeContext) Bytes(key string, val []byte) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Bytes(key, val) })
+}
+
+func (tc teeContext) Hex(key string, val []byte) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Hex(key, val) })
+}
+
+func (tc teeContext) RawJSON(key string, b []byte) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.RawJSON(key, b) })
+}
+
+func (tc teeContext) AnErr(key string, err error) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.AnErr(key, err) })
+}
+
+func (tc teeContext) Errs(key string, errs []error) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Errs(key, errs) })
+}
+
+func (tc teeContext) Err(err error) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Err(err) })
+}
+
+func (tc teeContext) Bool(key string, b bool) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Bool(key, b) })
+}
+
+func (tc teeContext) Bools(key string, b []bool) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Bools(key, b) })
+}
+
+func (tc teeContext) Int(key string, i int) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Int(key, i) })
+}
+
+func (tc teeContext) Ints(key string, i []int) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Ints(key, i) })
+}
+
+func (tc teeContext) Int8(key string, i int8) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Int8(key, i) })
+}
+
+func (tc teeContext) Ints8(key string, i []int8) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Ints8(key, i) })
+}
+
+func (tc teeContext) Int16(key string, i int16) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Int16(key, i) })
+}
+
+func (tc teeContext) Ints16(key string, i []int16) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Ints16(key, i) })
+}
+
+func (tc teeContext) Int32(key string, i int32) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Int32(key, i) })
+}
+
+func (tc teeContext) Ints32(key string, i []int32) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Ints32(key, i) })
+}
+
+func (tc teeContext) Int64(key string, i int64) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Int64(key, i) })
+}
+
+func (tc teeContext) Ints64(key string, i []int64) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Ints64(key, i) })
+}
+
+func (tc teeContext) Uint(key string, i uint) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Uint(key, i) })
+}
+
+func (tc teeContext) Uints(key string, i []uint) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Uints(key, i) })
+}
+
+func (tc teeContext) Uint8(key string, i uint8) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Uint8(key, i) })
+}
+
+func (tc teeContext) Uints8(key string, i []uint8) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Uints8(key, i) })
+}
+
+func (tc teeContext) Uint16(key string, i uint16) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Uint16(key, i) })
+}
+
+func (tc teeContext) Uints16(key string, i []uint16) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Uints16(key, i) })
+}
+
+func (tc teeContext) Uint32(key string, i uint32) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Uint32(key, i) })
+}
+
+func (tc teeContext) Uints32(key string, i []uint32) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Uints32(key, i) })
+}
+
+func (tc teeContext) Uint64(key string, i uint64) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Uint64(key, i) })
+}
+
+func (tc teeContext) Uints64(key string, i []uint64) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Uints64(key, i) })
+}
+
+func (tc teeContext) Float32(key string, f float32) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Float32(key, f) })
+}
+
+func (tc teeContext) Floats32(key string, f []float32) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Floats32(key, f) })
+}
+
+func (tc teeContext) Float64(key string, f float64) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Float64(key, f) })
+}
+
+func (tc teeContext) Floats64(key string, f []float64) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Floats64(key, f) })
+}
+
+func (tc teeContext) Timestamp() ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Timestamp() })
+}
+
+func (tc teeContext) Time(key string, t time.Time) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Time(key, t) })
+}
+
+func (tc teeContext) Times(key string, t []time.Time) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Times(key, t) })
+}
+
+func (tc teeContext) Dur(key string, d time.Duration) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Dur(key, d) })
+}
+
+func (tc teeContext) Durs(key string, d []time.Duration) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Durs(key, d) })
+}
+
+func (tc teeContext) Interface(key string, i interface{}) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Interface(key, i) })
+}
+
+func (tc teeContext) Stack() ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Stack() })
+}
+
+func (tc teeContext) IPAddr(key string, ip net.IP) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.IPAddr(key, ip) })
+}
+
+func (tc teeContext) IPPrefix(key string, pfx net.IPNet) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.IPPrefix(key, pfx) })
+}
+
+func (tc teeContext) MACAddr(key string, ha net.HardwareAddr) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.MACAddr(key, ha) })
+}
+
+func (tc teeContext) Fields(fields map[string]interface{}) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Fields(fields) })
+}
+
+func (tc teeContext) Dict(key string, dict ZeroEvent) ZeroContext {
+	return tc.with(func(c ZeroContext) ZeroContext { return c.Dict(key, dict) })
+}
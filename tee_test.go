@@ -0,0 +1,98 @@
+package ech0
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+func TestTee(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf1 := &strings.Builder{}
+	buf2 := &strings.Builder{}
+	z := Tee(Wrap(zerolog.New(buf1)).Level(zerolog.WarnLevel), Wrap(zerolog.New(buf2)).Level(zerolog.InfoLevel))
+
+	z.Info().Int("a", 1).Msg("m1")
+	z.Str("s", "x").Warn().Func(func(e ZeroEvent) { e.Int("b", 2) }).Msgf("m%d", 2)
+	z.With().Int("c", 3).Logger().Error().Send()
+	z.Debug().Msg("not logged")
+
+	g.Expect(buf1.String()).To(Equal(`{"level":"warn","s":"x","b":2,"message":"m2"}` + "\n" +
+		`{"level":"error","c":3}` + "\n"))
+	g.Expect(buf2.String()).To(Equal(`{"level":"info","a":1,"message":"m1"}` + "\n" +
+		`{"level":"warn","s":"x","b":2,"message":"m2"}` + "\n" +
+		`{"level":"error","c":3}` + "\n"))
+
+	g.Expect(z.Level(zerolog.ErrorLevel).Warn().Enabled()).To(BeFalse())
+}
+
+func TestTee_panic(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf1 := &strings.Builder{}
+	buf2 := &strings.Builder{}
+	z := Tee(Wrap(zerolog.New(buf1)), Wrap(zerolog.New(buf2)))
+
+	g.Expect(func() { z.Panic().Msg("p") }).To(PanicWith("p"))
+	g.Expect(buf1.String()).To(Equal(`{"level":"panic","message":"p"}` + "\n"))
+	g.Expect(buf2.String()).To(Equal(`{"level":"panic","message":"p"}` + "\n"))
+}
+
+func TestTee_dict(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf1 := &strings.Builder{}
+	buf2 := &strings.Builder{}
+	z := Tee(Wrap(zerolog.New(buf1)), Wrap(zerolog.New(buf2)))
+
+	z.Info().Dict("d", Dict().Str("a", "x").Int("b", 1)).Msg("m1")
+	z.With().Dict("c", Dict().Bool("ok", true)).Logger().Warn().Dict("d", Dict()).Send()
+
+	for _, s := range []string{buf1.String(), buf2.String()} {
+		g.Expect(s).To(Equal(`{"level":"info","d":{"a":"x","b":1},"message":"m1"}` + "\n" +
+			`{"level":"warn","c":{"ok":true},"d":{}}` + "\n"))
+	}
+}
+
+func TestTee_caller(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf1 := &strings.Builder{}
+	buf2 := &strings.Builder{}
+	z := Tee(Wrap(zerolog.New(buf1)), Wrap(zerolog.New(buf2)))
+
+	z.Info().Caller().Send()
+	z.With().Caller().Logger().Info().Send()
+	z.With().Caller().Logger().Info().Msgf("m")
+
+	for _, s := range []string{buf1.String(), buf2.String()} {
+		lines := strings.Split(strings.TrimSpace(s), "\n")
+		g.Expect(lines).To(HaveLen(3))
+		for _, line := range lines {
+			g.Expect(line).To(ContainSubstring(`tee_test.go:`))
+		}
+	}
+}
+
+// terminating is a logger that panics instead of exiting after a Fatal event.
+type terminating struct {
+	Zero
+}
+
+func (terminating) Terminate(code int) {
+	PanicOnExit(code)
+}
+
+func TestTee_fatal(t *testing.T) {
+	g := NewGomegaWithT(t)
+	w := &flushWriter{}
+	z := Tee(Wrap(zerolog.New(w)), terminating{Nop()}).Output(w)
+
+	g.Expect(func() { z.Fatal().Int("a", 1).Msg("f1") }).To(PanicWith(Exit{Code: 1}))
+	g.Expect(w.String()).To(Equal(`{"level":"fatal","a":1,"message":"f1"}` + "\n"))
+	g.Expect(w.flushed).To(Equal(1))
+
+	// no logger accepts the fatal event but the program still exits
+	g.Expect(func() { z.Level(zerolog.Disabled).Fatal().Msg("f2") }).To(PanicWith(Exit{Code: 1}))
+	g.Expect(w.String()).To(Equal(`{"level":"fatal","a":1,"message":"f1"}` + "\n"))
+	g.Expect(w.flushed).To(Equal(2))
+}
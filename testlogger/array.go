@@ -0,0 +1,149 @@
+package testlogger
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/rickb777/ech0/v3"
+)
+
+// TestLogArray captures the elements of an array as a linked list of TestLogEvents,
+// keyed by their index. It can also be replayed onto another array.
+type TestLogArray struct {
+	first, last *TestLogEvent
+	n           int
+	replay      []func(ech0.ZeroArray)
+}
+
+var _ ech0.ZeroArray = &TestLogArray{}
+
+// Arr creates an array that captures its elements. It can be used in place of ech0.Arr.
+func Arr() *TestLogArray {
+	return &TestLogArray{}
+}
+
+// First returns the first element, or nil if the array is empty.
+func (a *TestLogArray) First() *TestLogEvent {
+	return a.first
+}
+
+// MarshalZeroArray replays the captured elements onto t.
+func (a *TestLogArray) MarshalZeroArray(t ech0.ZeroArray) {
+	for _, op := range a.replay {
+		op(t)
+	}
+}
+
+func (a *TestLogArray) add(method string, val interface{}, op func(ech0.ZeroArray)) ech0.ZeroArray {
+	item := &TestLogEvent{Method: method, Key: strconv.Itoa(a.n), Val: val}
+	if a.first == nil {
+		a.first = item
+	} else {
+		a.last.Next = item
+	}
+	a.last = item
+	a.n++
+	a.replay = append(a.replay, op)
+	return a
+}
+
+func (a *TestLogArray) Object(obj ech0.ObjectMarshaler) ech0.ZeroArray {
+	return a.add("Object", captureObject(obj), func(target ech0.ZeroArray) { target.Object(obj) })
+}
+
+func (a *TestLogArray) Str(val string) ech0.ZeroArray {
+	return a.add("Str", val, func(target ech0.ZeroArray) { target.Str(val) })
+}
+
+func (a *TestLogArray) Bytes(val []byte) ech0.ZeroArray {
+	return a.add("Bytes", val, func(target ech0.ZeroArray) { target.Bytes(val) })
+}
+
+func (a *TestLogArray) Hex(val []byte) ech0.ZeroArray {
+	return a.add("Hex", val, func(target ech0.ZeroArray) { target.Hex(val) })
+}
+
+func (a *TestLogArray) RawJSON(val []byte) ech0.ZeroArray {
+	return a.add("RawJSON", val, func(target ech0.ZeroArray) { target.RawJSON(val) })
+}
+
+func (a *TestLogArray) Err(err error) ech0.ZeroArray {
+	return a.add("Err", err, func(target ech0.ZeroArray) { target.Err(err) })
+}
+
+func (a *TestLogArray) Bool(b bool) ech0.ZeroArray {
+	return a.add("Bool", b, func(target ech0.ZeroArray) { target.Bool(b) })
+}
+
+func (a *TestLogArray) Int(i int) ech0.ZeroArray {
+	return a.add("Int", i, func(target ech0.ZeroArray) { target.Int(i) })
+}
+
+func (a *TestLogArray) Int8(i int8) ech0.ZeroArray {
+	return a.add("Int8", i, func(target ech0.ZeroArray) { target.Int8(i) })
+}
+
+func (a *TestLogArray) Int16(i int16) ech0.ZeroArray {
+	return a.add("Int16", i, func(target ech0.ZeroArray) { target.Int16(i) })
+}
+
+func (a *TestLogArray) Int32(i int32) ech0.ZeroArray {
+	return a.add("Int32", i, func(target ech0.ZeroArray) { target.Int32(i) })
+}
+
+func (a *TestLogArray) Int64(i int64) ech0.ZeroArray {
+	return a.add("Int64", i, func(target ech0.ZeroArray) { target.Int64(i) })
+}
+
+func (a *TestLogArray) Uint(i uint) ech0.ZeroArray {
+	return a.add("Uint", i, func(target ech0.ZeroArray) { target.Uint(i) })
+}
+
+func (a *TestLogArray) Uint8(i uint8) ech0.ZeroArray {
+	return a.add("Uint8", i, func(target ech0.ZeroArray) { target.Uint8(i) })
+}
+
+func (a *TestLogArray) Uint16(i uint16) ech0.ZeroArray {
+	return a.add("Uint16", i, func(target ech0.ZeroArray) { target.Uint16(i) })
+}
+
+func (a *TestLogArray) Uint32(i uint32) ech0.ZeroArray {
+	return a.add("Uint32", i, func(target ech0.ZeroArray) { target.Uint32(i) })
+}
+
+func (a *TestLogArray) Uint64(i uint64) ech0.ZeroArray {
+	return a.add("Uint64", i, func(target ech0.ZeroArray) { target.Uint64(i) })
+}
+
+func (a *TestLogArray) Float32(f float32) ech0.ZeroArray {
+	return a.add("Float32", f, func(target ech0.ZeroArray) { target.Float32(f) })
+}
+
+func (a *TestLogArray) Float64(f float64) ech0.ZeroArray {
+	return a.add("Float64", f, func(target ech0.ZeroArray) { target.Float64(f) })
+}
+
+func (a *TestLogArray) Time(t time.Time) ech0.ZeroArray {
+	return a.add("Time", t, func(target ech0.ZeroArray) { target.Time(t) })
+}
+
+func (a *TestLogArray) Dur(d time.Duration) ech0.ZeroArray {
+	return a.add("Dur", d, func(target ech0.ZeroArray) { target.Dur(d) })
+}
+
+func (a *TestLogArray) Interface(i interface{}) ech0.ZeroArray {
+	return a.add("Interface", i, func(target ech0.ZeroArray) { target.Interface(i) })
+}
+
+func (a *TestLogArray) IPAddr(ip net.IP) ech0.ZeroArray {
+	return a.add("IPAddr", ip, func(target ech0.ZeroArray) { target.IPAddr(ip) })
+}
+
+func (a *TestLogArray) IPPrefix(pfx net.IPNet) ech0.ZeroArray {
+	return a.add("IPPrefix", pfx, func(target ech0.ZeroArray) { target.IPPrefix(pfx) })
+}
+
+func (a *TestLogArray) MACAddr(ha net.HardwareAddr) ech0.ZeroArray {
+	return a.add("MACAddr", ha, func(target ech0.ZeroArray) { target.MACAddr(ha) })
+}
@@ -0,0 +1,217 @@
+package testlogger
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// Fields holds the expected values of event fields, keyed by their names. Dotted keys
+// find fields in nested dictionaries, objects and arrays (see TestLogEvent.FindByKey).
+type Fields map[string]interface{}
+
+// AssertLogged checks that an event was logged at the given level with the given message
+// and with all the given fields; any other fields are ignored. Expected values are compared
+// with the captured values using reflect.DeepEqual, except that numbers of different types
+// can be compared and errors can be compared with strings.
+//
+// If there is no such event, the test is marked as failed, listing the differences for each
+// event that was logged at the same level. The result is true if the event was found.
+func AssertLogged(t testing.TB, tl *TestLogger, level zerolog.Level, msg string, fields ...Fields) bool {
+	t.Helper()
+	exp := expectation{level: level, msg: msg, fields: merge(fields)}
+	candidates := tl.atLevel(level)
+	if candidates.Exists(exp.matches) {
+		return true
+	}
+
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "expected %s\n", exp)
+	if candidates.IsEmpty() {
+		fmt.Fprintf(buf, "but no %s events were logged", level)
+	} else {
+		fmt.Fprintf(buf, "but found %d %s event(s):", candidates.Len(), level)
+		for i, ev := range candidates.ToSlice() {
+			fmt.Fprintf(buf, "\n  #%d %s", i+1, ev.Next)
+			for _, d := range exp.diff(ev) {
+				fmt.Fprintf(buf, "\n      %s", d)
+			}
+		}
+	}
+	t.Error(buf.String())
+	return false
+}
+
+// AssertNotLogged checks that no event was logged at the given level with the given
+// message and with all the given fields; see AssertLogged. The result is true if there
+// was no such event.
+func AssertNotLogged(t testing.TB, tl *TestLogger, level zerolog.Level, msg string, fields ...Fields) bool {
+	t.Helper()
+	exp := expectation{level: level, msg: msg, fields: merge(fields)}
+	found, exists := tl.atLevel(level).Find(exp.matches)
+	if !exists {
+		return true
+	}
+
+	t.Errorf("expected no %s\nbut found %s", exp, found.Next)
+	return false
+}
+
+// AssertCount checks that n events were logged at the given level. The result is true
+// if the count is correct.
+func AssertCount(t testing.TB, tl *TestLogger, level zerolog.Level, n int) bool {
+	t.Helper()
+	found := tl.atLevel(level)
+	if found.Len() == n {
+		return true
+	}
+
+	t.Errorf("expected %d %s event(s) but found %d%s", n, level, found.Len(), list(found))
+	return false
+}
+
+// RequireNoErrors stops the test immediately if any error, panic or fatal events were logged.
+func RequireNoErrors(t testing.TB, tl *TestLogger) {
+	t.Helper()
+	found := tl.atLevel(zerolog.ErrorLevel, zerolog.PanicLevel, zerolog.FatalLevel)
+	if found.NonEmpty() {
+		t.Fatalf("expected no errors but found %d%s", found.Len(), list(found))
+	}
+}
+
+// AssertTerminated checks that Msg or Send was called for every captured event, listing
+// where each unterminated event was created. The result is true if all were terminated.
+// NewT does this automatically when the test finishes.
+func AssertTerminated(t testing.TB, tl *TestLogger) bool {
+	t.Helper()
+	found := tl.All.Filter(func(ev *TestLogEvent) bool { return !ev.IsTerminated() })
+	if found.IsEmpty() {
+		return true
+	}
+
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "found %d log event(s) that were never sent; Msg or Send was not called", found.Len())
+	for _, ev := range found.ToSlice() {
+		fields := ev.Next.String()
+		if fields == "" {
+			fields = "<no fields>"
+		}
+		fmt.Fprintf(buf, "\n    %-5s %s created at %s", ev.Level(), fields, ev.callsite)
+	}
+	t.Error(buf.String())
+	return false
+}
+
+// atLevel filters All to find the events logged at any of the levels.
+func (l *TestLogger) atLevel(levels ...zerolog.Level) *TestLogEventList {
+	return l.All.Filter(func(ev *TestLogEvent) bool {
+		for _, lvl := range levels {
+			if ev.level == lvl {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func list(found *TestLogEventList) string {
+	buf := &strings.Builder{}
+	for _, ev := range found.ToSlice() {
+		fmt.Fprintf(buf, "\n    %-5s %s", ev.Level(), ev.Next)
+	}
+	return buf.String()
+}
+
+//-------------------------------------------------------------------------------------------------
+
+type expectation struct {
+	level  zerolog.Level
+	msg    string
+	fields Fields
+}
+
+func merge(fields []Fields) Fields {
+	all := make(Fields)
+	for _, f := range fields {
+		for k, v := range f {
+			all[k] = v
+		}
+	}
+	return all
+}
+
+func (exp expectation) keys() []string {
+	keys := make([]string, 0, len(exp.fields))
+	for k := range exp.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (exp expectation) String() string {
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "%s event with message %q", exp.level, exp.msg)
+	for _, k := range exp.keys() {
+		fmt.Fprintf(buf, "\n    %s: %#v", k, exp.fields[k])
+	}
+	return buf.String()
+}
+
+func (exp expectation) matches(ev *TestLogEvent) bool {
+	return len(exp.diff(ev)) == 0
+}
+
+// diff lists the ways in which ev differs from the expectation.
+func (exp expectation) diff(ev *TestLogEvent) []string {
+	var diffs []string
+	if msg := ev.Message(); msg != exp.msg {
+		diffs = append(diffs, fmt.Sprintf("message: got %q, want %q", msg, exp.msg))
+	}
+
+	for _, k := range exp.keys() {
+		item := ev.FindByKey(k)
+		switch {
+		case item == nil:
+			diffs = append(diffs, fmt.Sprintf("%s: missing, want %#v", k, exp.fields[k]))
+		case !equivalent(exp.fields[k], item.Value()):
+			diffs = append(diffs, fmt.Sprintf("%s: got %#v, want %#v", k, item.Value(), exp.fields[k]))
+		}
+	}
+	return diffs
+}
+
+// equivalent compares values using reflect.DeepEqual, except that numbers of different
+// types are compared by value and errors can be compared with strings.
+func equivalent(expected, actual interface{}) bool {
+	if reflect.DeepEqual(expected, actual) {
+		return true
+	}
+
+	if err, ok := actual.(error); ok {
+		if s, ok := expected.(string); ok {
+			return err.Error() == s
+		}
+	}
+
+	ev, aok := number(expected)
+	av, bok := number(actual)
+	return aok && bok && ev == av
+}
+
+func number(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
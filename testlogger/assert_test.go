@@ -0,0 +1,142 @@
+package testlogger
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+// recorder is a testing.TB that records failures instead of reporting them.
+type recorder struct {
+	testing.TB
+	failures []string
+	fatal    bool
+	logs     []string
+	cleanups []func()
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Failed() bool {
+	return len(r.failures) > 0
+}
+
+func (r *recorder) Log(args ...interface{}) {
+	r.logs = append(r.logs, fmt.Sprint(args...))
+}
+
+func (r *recorder) Cleanup(f func()) {
+	r.cleanups = append(r.cleanups, f)
+}
+
+func (r *recorder) finish() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+func (r *recorder) Error(args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprint(args...))
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+	r.fatal = true
+}
+
+func TestAssertLogged(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := New(nil)
+	tl.Info().Str("a", "1").Msg("started")
+	tl.Warn().Int("attempt", 2).Err(errors.New("e1")).Msg("retrying")
+
+	g.Expect(AssertLogged(t, tl, zerolog.WarnLevel, "retrying")).To(BeTrue())
+	g.Expect(AssertLogged(t, tl, zerolog.WarnLevel, "retrying", Fields{"attempt": int64(2), "error": "e1"})).To(BeTrue())
+	g.Expect(AssertNotLogged(t, tl, zerolog.InfoLevel, "retrying")).To(BeTrue())
+	g.Expect(AssertCount(t, tl, zerolog.WarnLevel, 1)).To(BeTrue())
+	RequireNoErrors(t, tl)
+
+	r := &recorder{}
+	g.Expect(AssertLogged(r, tl, zerolog.WarnLevel, "retried", Fields{"attempt": 3, "id": "x"})).To(BeFalse())
+	g.Expect(r.failures).To(Equal([]string{`expected warn event with message "retried"
+    attempt: 3
+    id: "x"
+but found 1 warn event(s):
+  #1 Int(attempt, 2).Err(error, e1).Msg(retrying)
+      message: got "retrying", want "retried"
+      attempt: got 2, want 3
+      id: missing, want "x"`}))
+
+	r = &recorder{}
+	g.Expect(AssertLogged(r, tl, zerolog.ErrorLevel, "failed")).To(BeFalse())
+	g.Expect(r.failures).To(Equal([]string{`expected error event with message "failed"
+but no error events were logged`}))
+
+	r = &recorder{}
+	g.Expect(AssertNotLogged(r, tl, zerolog.InfoLevel, "started", Fields{"a": "1"})).To(BeFalse())
+	g.Expect(r.failures).To(Equal([]string{`expected no info event with message "started"
+    a: "1"
+but found Str(a, 1).Msg(started)`}))
+
+	r = &recorder{}
+	g.Expect(AssertCount(r, tl, zerolog.InfoLevel, 2)).To(BeFalse())
+	g.Expect(r.failures).To(Equal([]string{"expected 2 info event(s) but found 1\n    info  Str(a, 1).Msg(started)"}))
+}
+
+func TestRequireNoErrors(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := New(nil, OnExit(func(int) {}))
+	tl.Fatal().Msg("f1")
+	tl.Error().Int("a", 1).Msg("e1")
+
+	r := &recorder{}
+	RequireNoErrors(r, tl)
+
+	g.Expect(r.fatal).To(BeTrue())
+	g.Expect(r.failures).To(Equal([]string{"expected no errors but found 2\n    fatal Msg(f1)\n    error Int(a, 1).Msg(e1)"}))
+}
+
+func TestAssertTerminated(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := New(nil)
+	tl.Info().Msg("sent")
+	g.Expect(AssertTerminated(t, tl)).To(BeTrue())
+
+	tl.Warn().Str("b", "y")
+	_, file, line, _ := runtime.Caller(0)
+	tl.Error()
+
+	r := &recorder{}
+	g.Expect(AssertTerminated(r, tl)).To(BeFalse())
+	g.Expect(r.failures).To(HaveLen(1))
+	g.Expect(r.failures[0]).To(HavePrefix("found 2 log event(s) that were never sent; Msg or Send was not called\n"))
+	g.Expect(r.failures[0]).To(ContainSubstring(fmt.Sprintf("\n    warn  Str(b, y) created at %s:%d\n", file, line-1)))
+	g.Expect(r.failures[0]).To(ContainSubstring(fmt.Sprintf("\n    error <no fields> created at %s:%d", file, line+1)))
+}
+
+func TestNewT_unterminated(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	r := &recorder{}
+	tl := NewT(r)
+	tl.Info().Int("a", 1)
+	r.finish()
+
+	g.Expect(r.failures).To(HaveLen(1))
+	g.Expect(r.logs).To(Equal([]string{"captured 1 log event(s)\n    info  Int(a, 1)"}))
+
+	r = &recorder{}
+	tl = NewT(r, AllowUnterminated())
+	tl.Info().Int("a", 1)
+	r.finish()
+
+	g.Expect(r.failures).To(BeEmpty())
+}
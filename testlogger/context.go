@@ -0,0 +1,244 @@
+package testlogger
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/rickb777/ech0/v3"
+)
+
+// testContext builds a child logger of a TestLogger. Fields are recorded, so that they
+// can be included in every captured event, and are passed on to the real logger's context.
+type testContext struct {
+	l      *TestLogger
+	real   ech0.ZeroContext
+	fields []field
+}
+
+var _ ech0.ZeroContext = &testContext{}
+
+// With creates a child logger builder, to which any fields can be added.
+func (l *TestLogger) With() ech0.ZeroContext {
+	return &testContext{l: l, real: l.realLogger.With()}
+}
+
+// Logger returns a child logger with the context fields.
+func (c *testContext) Logger() ech0.Zero {
+	return c.l.child(c.real.Logger(), c.fields...)
+}
+
+func (c *testContext) with(method, key string, val interface{}, fn func(ech0.ZeroContext) ech0.ZeroContext) ech0.ZeroContext {
+	n := len(c.fields)
+	fields := append(c.fields[:n:n], field{method: method, key: key, val: val})
+	return &testContext{l: c.l, real: fn(c.real), fields: fields}
+}
+
+func (c *testContext) Str(key, val string) ech0.ZeroContext {
+	return c.with("Str", key, val, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Str(key, val) })
+}
+
+func (c *testContext) Strs(key string, vals []string) ech0.ZeroContext {
+	return c.with("Strs", key, vals, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Strs(key, vals) })
+}
+
+func (c *testContext) Stringer(key string, val fmt.Stringer) ech0.ZeroContext {
+	return c.with("Stringer", key, val, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Stringer(key, val) })
+}
+
+func (c *testContext) Bytes(key string, val []byte) ech0.ZeroContext {
+	return c.with("Bytes", key, val, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Bytes(key, val) })
+}
+
+func (c *testContext) Hex(key string, val []byte) ech0.ZeroContext {
+	return c.with("Hex", key, val, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Hex(key, val) })
+}
+
+func (c *testContext) RawJSON(key string, b []byte) ech0.ZeroContext {
+	return c.with("RawJSON", key, b, func(real ech0.ZeroContext) ech0.ZeroContext { return real.RawJSON(key, b) })
+}
+
+func (c *testContext) AnErr(key string, err error) ech0.ZeroContext {
+	return c.with("AnErr", key, err, func(real ech0.ZeroContext) ech0.ZeroContext { return real.AnErr(key, err) })
+}
+
+func (c *testContext) Errs(key string, errs []error) ech0.ZeroContext {
+	return c.with("Errs", key, errs, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Errs(key, errs) })
+}
+
+func (c *testContext) Err(err error) ech0.ZeroContext {
+	return c.with("Err", "error", err, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Err(err) })
+}
+
+func (c *testContext) Bool(key string, b bool) ech0.ZeroContext {
+	return c.with("Bool", key, b, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Bool(key, b) })
+}
+
+func (c *testContext) Bools(key string, b []bool) ech0.ZeroContext {
+	return c.with("Bools", key, b, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Bools(key, b) })
+}
+
+func (c *testContext) Int(key string, i int) ech0.ZeroContext {
+	return c.with("Int", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Int(key, i) })
+}
+
+func (c *testContext) Ints(key string, i []int) ech0.ZeroContext {
+	return c.with("Ints", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Ints(key, i) })
+}
+
+func (c *testContext) Int8(key string, i int8) ech0.ZeroContext {
+	return c.with("Int8", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Int8(key, i) })
+}
+
+func (c *testContext) Ints8(key string, i []int8) ech0.ZeroContext {
+	return c.with("Ints8", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Ints8(key, i) })
+}
+
+func (c *testContext) Int16(key string, i int16) ech0.ZeroContext {
+	return c.with("Int16", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Int16(key, i) })
+}
+
+func (c *testContext) Ints16(key string, i []int16) ech0.ZeroContext {
+	return c.with("Ints16", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Ints16(key, i) })
+}
+
+func (c *testContext) Int32(key string, i int32) ech0.ZeroContext {
+	return c.with("Int32", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Int32(key, i) })
+}
+
+func (c *testContext) Ints32(key string, i []int32) ech0.ZeroContext {
+	return c.with("Ints32", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Ints32(key, i) })
+}
+
+func (c *testContext) Int64(key string, i int64) ech0.ZeroContext {
+	return c.with("Int64", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Int64(key, i) })
+}
+
+func (c *testContext) Ints64(key string, i []int64) ech0.ZeroContext {
+	return c.with("Ints64", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Ints64(key, i) })
+}
+
+func (c *testContext) Uint(key string, i uint) ech0.ZeroContext {
+	return c.with("Uint", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Uint(key, i) })
+}
+
+func (c *testContext) Uints(key string, i []uint) ech0.ZeroContext {
+	return c.with("Uints", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Uints(key, i) })
+}
+
+func (c *testContext) Uint8(key string, i uint8) ech0.ZeroContext {
+	return c.with("Uint8", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Uint8(key, i) })
+}
+
+func (c *testContext) Uints8(key string, i []uint8) ech0.ZeroContext {
+	return c.with("Uints8", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Uints8(key, i) })
+}
+
+func (c *testContext) Uint16(key string, i uint16) ech0.ZeroContext {
+	return c.with("Uint16", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Uint16(key, i) })
+}
+
+func (c *testContext) Uints16(key string, i []uint16) ech0.ZeroContext {
+	return c.with("Uints16", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Uints16(key, i) })
+}
+
+func (c *testContext) Uint32(key string, i uint32) ech0.ZeroContext {
+	return c.with("Uint32", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Uint32(key, i) })
+}
+
+func (c *testContext) Uints32(key string, i []uint32) ech0.ZeroContext {
+	return c.with("Uints32", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Uints32(key, i) })
+}
+
+func (c *testContext) Uint64(key string, i uint64) ech0.ZeroContext {
+	return c.with("Uint64", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Uint64(key, i) })
+}
+
+func (c *testContext) Uints64(key string, i []uint64) ech0.ZeroContext {
+	return c.with("Uints64", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Uints64(key, i) })
+}
+
+func (c *testContext) Float32(key string, f float32) ech0.ZeroContext {
+	return c.with("Float32", key, f, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Float32(key, f) })
+}
+
+func (c *testContext) Floats32(key string, f []float32) ech0.ZeroContext {
+	return c.with("Floats32", key, f, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Floats32(key, f) })
+}
+
+func (c *testContext) Float64(key string, f float64) ech0.ZeroContext {
+	return c.with("Float64", key, f, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Float64(key, f) })
+}
+
+func (c *testContext) Floats64(key string, f []float64) ech0.ZeroContext {
+	return c.with("Floats64", key, f, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Floats64(key, f) })
+}
+
+func (c *testContext) Timestamp() ech0.ZeroContext {
+	return c.with("Timestamp", "", nil, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Timestamp() })
+}
+
+func (c *testContext) Time(key string, t time.Time) ech0.ZeroContext {
+	return c.with("Time", key, t, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Time(key, t) })
+}
+
+func (c *testContext) Times(key string, t []time.Time) ech0.ZeroContext {
+	return c.with("Times", key, t, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Times(key, t) })
+}
+
+func (c *testContext) Dur(key string, d time.Duration) ech0.ZeroContext {
+	return c.with("Dur", key, d, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Dur(key, d) })
+}
+
+func (c *testContext) Durs(key string, d []time.Duration) ech0.ZeroContext {
+	return c.with("Durs", key, d, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Durs(key, d) })
+}
+
+func (c *testContext) Interface(key string, i interface{}) ech0.ZeroContext {
+	return c.with("Interface", key, i, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Interface(key, i) })
+}
+
+func (c *testContext) Stack() ech0.ZeroContext {
+	return c.with("Stack", "", nil, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Stack() })
+}
+
+func (c *testContext) IPAddr(key string, ip net.IP) ech0.ZeroContext {
+	return c.with("IPAddr", key, ip, func(real ech0.ZeroContext) ech0.ZeroContext { return real.IPAddr(key, ip) })
+}
+
+func (c *testContext) IPPrefix(key string, pfx net.IPNet) ech0.ZeroContext {
+	return c.with("IPPrefix", key, pfx, func(real ech0.ZeroContext) ech0.ZeroContext { return real.IPPrefix(key, pfx) })
+}
+
+func (c *testContext) MACAddr(key string, ha net.HardwareAddr) ech0.ZeroContext {
+	return c.with("MACAddr", key, ha, func(real ech0.ZeroContext) ech0.ZeroContext { return real.MACAddr(key, ha) })
+}
+
+// Fields records each field separately, in key order, using Interface.
+func (c *testContext) Fields(fields map[string]interface{}) ech0.ZeroContext {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	n := len(c.fields)
+	recorded := c.fields[:n:n]
+	for _, k := range keys {
+		recorded = append(recorded, field{method: "Interface", key: k, val: fields[k]})
+	}
+	return &testContext{l: c.l, real: c.real.Fields(fields), fields: recorded}
+}
+
+func (c *testContext) Dict(key string, dict ech0.ZeroEvent) ech0.ZeroContext {
+	return c.with("Dict", key, captureDict(dict), func(real ech0.ZeroContext) ech0.ZeroContext { return real.Dict(key, dict) })
+}
+
+func (c *testContext) Caller() ech0.ZeroContext {
+	return c.with("Caller", "", nil, func(real ech0.ZeroContext) ech0.ZeroContext { return real.Caller() })
+}
+
+func (c *testContext) CallerWithSkipFrameCount(skipFrameCount int) ech0.ZeroContext {
+	return c.with("CallerWithSkipFrameCount", "", skipFrameCount, func(real ech0.ZeroContext) ech0.ZeroContext { return real.CallerWithSkipFrameCount(skipFrameCount) })
+}
@@ -5,8 +5,14 @@ package testlogger
 import (
 	"fmt"
 	"github.com/rickb777/ech0/v3"
+	"net"
+	"reflect"
+	"runtime"
 	"strings"
+	"sync/atomic"
 	"time"
+
+	"github.com/rs/zerolog"
 )
 
 // TestLogEvent describes one item in a linked list that holds a single log message.
@@ -16,6 +22,15 @@ type TestLogEvent struct {
 	Val         interface{}
 	Next        *TestLogEvent
 	done        func(msg string)
+	head        *TestLogEvent       // the first item in the list; nil for the first item itself
+	owners      []*TestLogEventList // the lists holding the first item, if any
+	discarded   bool                // only used by the first item
+	level       zerolog.Level       // only used by the first item
+	seq         uint64              // only used by the first item
+	at          time.Time           // only used by the first item
+	callsite    string              // only used by the first item
+	order       *order              // only used by the first item, if captured
+	sent        uint32              // only used by the first item; set atomically by Msg and Send
 }
 
 var _ ech0.ZeroEvent = &TestLogEvent{}
@@ -47,8 +62,9 @@ func (ev *TestLogEvent) Send() {
 	if ev.realEvent != nil {
 		ev.realEvent.Send()
 	}
-	ev.Next = &TestLogEvent{Method: "Send"}
-	if ev.done != nil {
+	ev.tail().Next = &TestLogEvent{Method: "Send", head: ev.root()}
+	ev.root().markSent()
+	if ev.done != nil && ev.Enabled() {
 		ev.done("")
 	}
 }
@@ -57,8 +73,9 @@ func (ev *TestLogEvent) Msg(s string) {
 	if ev.realEvent != nil {
 		ev.realEvent.Msg(s)
 	}
-	ev.Next = &TestLogEvent{Method: "Msg", Val: s}
-	if ev.done != nil {
+	ev.tail().Next = &TestLogEvent{Method: "Msg", Val: s, head: ev.root()}
+	ev.root().markSent()
+	if ev.done != nil && ev.Enabled() {
 		ev.done(s)
 	}
 }
@@ -67,9 +84,53 @@ func (ev *TestLogEvent) Msgf(format string, v ...interface{}) {
 	ev.Msg(fmt.Sprintf(format, v...))
 }
 
+// Enabled returns false if the event has been discarded.
+func (ev *TestLogEvent) Enabled() bool {
+	return !ev.root().discarded
+}
+
+// Discard disables the event and removes it from the list of captured events.
+func (ev *TestLogEvent) Discard() ech0.ZeroEvent {
+	if ev.realEvent != nil {
+		ev.realEvent.Discard()
+	}
+
+	first := ev.root()
+	first.discarded = true
+	for _, owner := range first.owners {
+		owner.DoKeepWhere(func(e *TestLogEvent) bool { return e != first })
+	}
+	return ev
+}
+
+// Func allows an anonymous func to run only if the event is enabled.
+func (ev *TestLogEvent) Func(f func(e ech0.ZeroEvent)) ech0.ZeroEvent {
+	if ev.Enabled() {
+		f(ev)
+	}
+	return ev.tail()
+}
+
+func (ev *TestLogEvent) root() *TestLogEvent {
+	if ev.head != nil {
+		return ev.head
+	}
+	return ev
+}
+
+func (ev *TestLogEvent) tail() *TestLogEvent {
+	tail := ev
+	for tail.Next != nil {
+		tail = tail.Next
+	}
+	return tail
+}
+
+// add appends a new item to the end of the list. Normally ev is the last item already,
+// but marshalers may add several fields to the same event without chaining.
 func (ev *TestLogEvent) add(re ech0.ZeroEvent, method, key string, val interface{}) ech0.ZeroEvent {
-	next := &TestLogEvent{realEvent: re, Method: method, Key: key, Val: val, done: ev.done}
-	ev.Next = next
+	next := &TestLogEvent{realEvent: re, Method: method, Key: key, Val: val, done: ev.done, head: ev.root()}
+	ev.tail().Next = next
 	return next
 }
 
@@ -113,12 +174,70 @@ func (ev *TestLogEvent) Dur(key string, val time.Duration) ech0.ZeroEvent {
 	return ev.add(re, "Dur", key, val)
 }
 
+// Dict captures the dictionary's fields as a nested list of TestLogEvents, which is the value.
+// The dictionary can be created by either Dict or ech0.Dict.
 func (ev *TestLogEvent) Dict(key string, dict ech0.ZeroEvent) ech0.ZeroEvent {
 	var re ech0.ZeroEvent
 	if ev.realEvent != nil {
 		re = ev.realEvent.Dict(key, dict)
 	}
-	return ev.add(re, "Dict", key, dict)
+
+	return ev.add(re, "Dict", key, captureDict(dict))
+}
+
+// captureDict returns the fields of a dictionary as a list of TestLogEvents,
+// or nil if there are none.
+func captureDict(dict ech0.ZeroEvent) interface{} {
+	var fields *TestLogEvent
+	switch d := dict.(type) {
+	case *TestLogEvent:
+		fields = d.root().Next
+	case ech0.ObjectMarshaler:
+		fields = captureObject(d)
+	}
+
+	if fields == nil {
+		return nil
+	}
+	return fields
+}
+
+// Dict creates a dictionary that captures its fields. It can be used in place of ech0.Dict.
+func Dict() *TestLogEvent {
+	return &TestLogEvent{realEvent: ech0.Dict()}
+}
+
+// Object captures the object's fields as a nested list of TestLogEvents, which is the value.
+func (ev *TestLogEvent) Object(key string, obj ech0.ObjectMarshaler) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Object(key, obj)
+	}
+	return ev.add(re, "Object", key, captureObject(obj))
+}
+
+// EmbedObject captures the object's fields as if they had been added individually.
+func (ev *TestLogEvent) EmbedObject(obj ech0.ObjectMarshaler) ech0.ZeroEvent {
+	obj.MarshalZeroObject(ev.tail()) // also passes the fields to the real event
+	return ev.tail()
+}
+
+// Array captures the array's elements as a nested list of TestLogEvents, which is the value.
+// Their keys are the indexes "0", "1" etc.
+func (ev *TestLogEvent) Array(key string, arr ech0.ArrayMarshaler) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Array(key, arr)
+	}
+	ta := &TestLogArray{}
+	arr.MarshalZeroArray(ta)
+	return ev.add(re, "Array", key, ta.First())
+}
+
+func captureObject(obj ech0.ObjectMarshaler) *TestLogEvent {
+	head := &TestLogEvent{}
+	obj.MarshalZeroObject(head)
+	return head.Next
 }
 
 func (ev *TestLogEvent) Err(err error) ech0.ZeroEvent {
@@ -129,6 +248,34 @@ func (ev *TestLogEvent) Err(err error) ech0.ZeroEvent {
 	return ev.add(re, "Err", "error", err)
 }
 
+// Stack is recorded with no key or value; the stack itself is only written by the real logger.
+func (ev *TestLogEvent) Stack() ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Stack()
+	}
+	return ev.add(re, "Stack", "", nil)
+}
+
+// Caller records the file:line of the caller, formatted by zerolog.CallerMarshalFunc.
+func (ev *TestLogEvent) Caller(skip ...int) ech0.ZeroEvent {
+	sk := 0
+	if len(skip) > 0 {
+		sk = skip[0]
+	}
+
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Caller(sk + 1)
+	}
+
+	var caller string
+	if _, file, line, ok := runtime.Caller(sk + 1); ok {
+		caller = zerolog.CallerMarshalFunc(file, line)
+	}
+	return ev.add(re, "Caller", zerolog.CallerFieldName, caller)
+}
+
 func (ev *TestLogEvent) Hex(key string, val []byte) ech0.ZeroEvent {
 	var re ech0.ZeroEvent
 	if ev.realEvent != nil {
@@ -233,16 +380,381 @@ func (ev *TestLogEvent) Uint64(key string, val uint64) ech0.ZeroEvent {
 	return ev.add(re, "Uint64", key, val)
 }
 
+func (ev *TestLogEvent) Errs(key string, errs []error) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Errs(key, errs)
+	}
+	return ev.add(re, "Errs", key, errs)
+}
+
+func (ev *TestLogEvent) Float32(key string, val float32) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Float32(key, val)
+	}
+	return ev.add(re, "Float32", key, val)
+}
+
+func (ev *TestLogEvent) Floats32(key string, val []float32) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Floats32(key, val)
+	}
+	return ev.add(re, "Floats32", key, val)
+}
+
+func (ev *TestLogEvent) Float64(key string, val float64) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Float64(key, val)
+	}
+	return ev.add(re, "Float64", key, val)
+}
+
+func (ev *TestLogEvent) Floats64(key string, val []float64) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Floats64(key, val)
+	}
+	return ev.add(re, "Floats64", key, val)
+}
+
+func (ev *TestLogEvent) Int8(key string, val int8) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Int8(key, val)
+	}
+	return ev.add(re, "Int8", key, val)
+}
+
+func (ev *TestLogEvent) Int16(key string, val int16) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Int16(key, val)
+	}
+	return ev.add(re, "Int16", key, val)
+}
+
+func (ev *TestLogEvent) Int32(key string, val int32) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Int32(key, val)
+	}
+	return ev.add(re, "Int32", key, val)
+}
+
+func (ev *TestLogEvent) Ints8(key string, val []int8) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Ints8(key, val)
+	}
+	return ev.add(re, "Ints8", key, val)
+}
+
+func (ev *TestLogEvent) Ints16(key string, val []int16) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Ints16(key, val)
+	}
+	return ev.add(re, "Ints16", key, val)
+}
+
+func (ev *TestLogEvent) Ints32(key string, val []int32) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Ints32(key, val)
+	}
+	return ev.add(re, "Ints32", key, val)
+}
+
+func (ev *TestLogEvent) Ints64(key string, val []int64) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Ints64(key, val)
+	}
+	return ev.add(re, "Ints64", key, val)
+}
+
+func (ev *TestLogEvent) Uint8(key string, val uint8) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Uint8(key, val)
+	}
+	return ev.add(re, "Uint8", key, val)
+}
+
+func (ev *TestLogEvent) Uint16(key string, val uint16) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Uint16(key, val)
+	}
+	return ev.add(re, "Uint16", key, val)
+}
+
+func (ev *TestLogEvent) Uint32(key string, val uint32) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Uint32(key, val)
+	}
+	return ev.add(re, "Uint32", key, val)
+}
+
+func (ev *TestLogEvent) Uints8(key string, val []uint8) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Uints8(key, val)
+	}
+	return ev.add(re, "Uints8", key, val)
+}
+
+func (ev *TestLogEvent) Uints16(key string, val []uint16) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Uints16(key, val)
+	}
+	return ev.add(re, "Uints16", key, val)
+}
+
+func (ev *TestLogEvent) Uints32(key string, val []uint32) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Uints32(key, val)
+	}
+	return ev.add(re, "Uints32", key, val)
+}
+
+func (ev *TestLogEvent) Uints64(key string, val []uint64) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Uints64(key, val)
+	}
+	return ev.add(re, "Uints64", key, val)
+}
+
+func (ev *TestLogEvent) Times(key string, val []time.Time) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Times(key, val)
+	}
+	return ev.add(re, "Times", key, val)
+}
+
+func (ev *TestLogEvent) Durs(key string, val []time.Duration) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.Durs(key, val)
+	}
+	return ev.add(re, "Durs", key, val)
+}
+
+func (ev *TestLogEvent) TimeDiff(key string, t time.Time, start time.Time) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.TimeDiff(key, t, start)
+	}
+	var d time.Duration
+	if t.After(start) {
+		d = t.Sub(start)
+	}
+	return ev.add(re, "TimeDiff", key, d)
+}
+
+func (ev *TestLogEvent) IPAddr(key string, ip net.IP) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.IPAddr(key, ip)
+	}
+	return ev.add(re, "IPAddr", key, ip)
+}
+
+func (ev *TestLogEvent) IPPrefix(key string, pfx net.IPNet) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.IPPrefix(key, pfx)
+	}
+	return ev.add(re, "IPPrefix", key, pfx)
+}
+
+func (ev *TestLogEvent) MACAddr(key string, ha net.HardwareAddr) ech0.ZeroEvent {
+	var re ech0.ZeroEvent
+	if ev.realEvent != nil {
+		re = ev.realEvent.MACAddr(key, ha)
+	}
+	return ev.add(re, "MACAddr", key, ha)
+}
+
 // FindByKey searches through the linked list of TestLogEvents to find the (first)
 // one with a given key, or the end of the list (nil). Use with Value.
+//
+// Nested dictionaries, objects and arrays can be searched using dotted keys,
+// e.g. "user.name" or "users.0.name".
 func (ev *TestLogEvent) FindByKey(key string) *TestLogEvent {
-	if ev == nil {
-		return nil
+	if found := ev.findByKey(key); found != nil {
+		return found
+	}
+
+	for i := 0; i < len(key); i++ {
+		if key[i] == '.' {
+			if sub, ok := ev.findByKey(key[:i]).Value().(*TestLogEvent); ok {
+				if found := sub.FindByKey(key[i+1:]); found != nil {
+					return found
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (ev *TestLogEvent) findByKey(key string) *TestLogEvent {
+	for ; ev != nil; ev = ev.Next {
+		if ev.Key == key {
+			return ev
+		}
 	}
-	if ev.Key == key {
-		return ev
+	return nil
+}
+
+// Level returns the level of the event.
+func (ev *TestLogEvent) Level() zerolog.Level {
+	return ev.root().level
+}
+
+// Seq returns the sequence number of the event. Events are numbered from 1 in the order
+// they were logged, across all levels, by a TestLogger and its child loggers.
+// Events that were not captured have sequence number 0.
+func (ev *TestLogEvent) Seq() uint64 {
+	return ev.root().seq
+}
+
+// CapturedAt returns the time when the event was captured.
+func (ev *TestLogEvent) CapturedAt() time.Time {
+	return ev.root().at
+}
+
+// Message returns the message of the event, which is blank if Msg has not been called.
+func (ev *TestLogEvent) Message() string {
+	for item := ev.root(); item != nil; item = item.Next {
+		if item.Method == "Msg" {
+			return item.Val.(string)
+		}
+	}
+	return ""
+}
+
+// Fields returns the fields of the event, keyed by their names; the message is not included.
+// Nested dictionaries and objects are returned as Fields and arrays as []interface{}.
+// If a key occurs more than once, the first value is used, as with FindByKey.
+func (ev *TestLogEvent) Fields() Fields {
+	fields := make(Fields)
+	for item := ev.root(); item != nil; item = item.Next {
+		if _, exists := fields[item.Key]; item.Key != "" && !exists {
+			fields[item.Key] = nestedValue(item)
+		}
+	}
+	return fields
+}
+
+// Keys returns the names of the fields of the event, in the order they were added.
+// Each name is listed once; the message is not included.
+func (ev *TestLogEvent) Keys() []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for item := ev.root(); item != nil; item = item.Next {
+		if item.Key != "" && !seen[item.Key] {
+			seen[item.Key] = true
+			keys = append(keys, item.Key)
+		}
+	}
+	return keys
+}
+
+// HasKey returns true if the event has the field key. Dotted keys find fields in nested
+// dictionaries, objects and arrays (see FindByKey).
+func (ev *TestLogEvent) HasKey(key string) bool {
+	return key != "" && ev.root().FindByKey(key) != nil
+}
+
+// Get returns the value of the field key, and whether the event has the field.
+// Dotted keys find fields in nested dictionaries, objects and arrays (see FindByKey).
+func (ev *TestLogEvent) Get(key string) (interface{}, bool) {
+	if key == "" {
+		return nil, false
+	}
+	item := ev.root().FindByKey(key)
+	return item.Value(), item != nil
+}
+
+// GetInt returns the value of the field key, which may hold any signed or unsigned
+// integer type. The result is false if the field is missing or is not an integer.
+func (ev *TestLogEvent) GetInt(key string) (int, bool) {
+	v, _ := ev.Get(key)
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(rv.Uint()), true
+	}
+	return 0, false
+}
+
+// GetStr returns the value of the field key, which may hold a string or a fmt.Stringer.
+// The result is false if the field is missing or is not a string.
+func (ev *TestLogEvent) GetStr(key string) (string, bool) {
+	v, _ := ev.Get(key)
+	switch s := v.(type) {
+	case string:
+		return s, true
+	case fmt.Stringer:
+		return s.String(), true
+	}
+	return "", false
+}
+
+// GetErr returns the value of the field key, such as "error" for the field added by Err.
+// The result is nil if the field is missing or is not an error.
+func (ev *TestLogEvent) GetErr(key string) error {
+	v, _ := ev.Get(key)
+	err, _ := v.(error)
+	return err
+}
+
+// GetDur returns the value of the field key. The result is false if the field is missing
+// or is not a time.Duration.
+func (ev *TestLogEvent) GetDur(key string) (time.Duration, bool) {
+	v, _ := ev.Get(key)
+	d, ok := v.(time.Duration)
+	return d, ok
+}
+
+// Callsite returns the file:line where a captured event was created, which is blank for
+// events that were not captured.
+func (ev *TestLogEvent) Callsite() string {
+	return ev.root().callsite
+}
+
+// IsTerminated returns true if Msg, Msgf or Send has been called. Events that were
+// built but never sent are not terminated. It is safe to call while other goroutines
+// are logging.
+func (ev *TestLogEvent) IsTerminated() bool {
+	return atomic.LoadUint32(&ev.root().sent) != 0
+}
+
+func nestedValue(item *TestLogEvent) interface{} {
+	sub, ok := item.Val.(*TestLogEvent)
+	switch {
+	case !ok:
+		return item.Val
+	case item.Method == "Array":
+		var elements []interface{}
+		for ; sub != nil; sub = sub.Next {
+			elements = append(elements, nestedValue(sub))
+		}
+		return elements
+	default:
+		return sub.Fields()
 	}
-	return ev.Next.FindByKey(key)
 }
 
 // Value returns the value of one list item. The item may be nil, in which
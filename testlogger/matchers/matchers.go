@@ -0,0 +1,281 @@
+// Package matchers provides Gomega matchers for the log events captured by a
+// testlogger.TestLogger.
+//
+// Each matcher accepts a *testlogger.TestLogger, a *testlogger.TestLogEventList or a
+// single *testlogger.TestLogEvent. With a logger or a list, the matcher succeeds if any
+// of the captured events matches.
+//
+//	g.Expect(tl).To(HaveLogged(zerolog.WarnLevel, "retrying", Fields{"attempt": 2}))
+//	g.Expect(tl.Errors).To(HaveField("request_id", Not(BeEmpty())))
+//	g.Expect(tl.LastInfo()).To(HaveMessage(HavePrefix("started")))
+//
+// To wait for events logged by other goroutines, use TestLogger.Sent with Eventually:
+//
+//	g.Eventually(tl.Sent).Should(HaveLogged(zerolog.InfoLevel, "done"))
+//
+// Expected values can be given as plain values or as Gomega matchers. Plain values are
+// compared using BeEquivalentTo, except that errors can be compared with strings.
+package matchers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+	"github.com/rickb777/ech0/v3/testlogger"
+	"github.com/rs/zerolog"
+)
+
+// Fields holds the expected values of event fields, keyed by their names. Dotted keys
+// find fields in nested dictionaries, objects and arrays (see TestLogEvent.FindByKey).
+type Fields = testlogger.Fields
+
+// HaveLogged succeeds if an event was logged at the given level, with the given message
+// and with all the given fields. Any other fields are ignored.
+func HaveLogged(level zerolog.Level, message interface{}, fields ...Fields) types.GomegaMatcher {
+	ms := []types.GomegaMatcher{HaveLevel(level), HaveMessage(message)}
+	desc := []string{fmt.Sprintf("level %s", level), fmt.Sprintf("message %s", describe(message))}
+	for _, f := range fields {
+		keys := make([]string, 0, len(f))
+		for k := range f {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			ms = append(ms, HaveField(k, f[k]))
+			desc = append(desc, fmt.Sprintf("field %s %s", k, describe(f[k])))
+		}
+	}
+	return &eventMatcher{
+		desc:  "have logged an event with " + strings.Join(desc, ", "),
+		match: allOf(ms),
+	}
+}
+
+// HaveLevel succeeds if an event was logged at the given level.
+func HaveLevel(level zerolog.Level) types.GomegaMatcher {
+	return &eventMatcher{
+		desc: fmt.Sprintf("have an event with level %s", level),
+		match: func(ev *testlogger.TestLogEvent) (bool, error) {
+			return ev.Level() == level, nil
+		},
+	}
+}
+
+// HaveMessage succeeds if an event has a message that matches expected.
+func HaveMessage(expected interface{}) types.GomegaMatcher {
+	return &eventMatcher{
+		desc: "have an event with message " + describe(expected),
+		match: func(ev *testlogger.TestLogEvent) (bool, error) {
+			return matchValue(expected, ev.Message()), nil
+		},
+	}
+}
+
+// HaveField succeeds if an event has the field key, with a value that matches expected.
+func HaveField(key string, expected interface{}) types.GomegaMatcher {
+	return &eventMatcher{
+		desc: fmt.Sprintf("have an event with field %s %s", key, describe(expected)),
+		match: func(ev *testlogger.TestLogEvent) (bool, error) {
+			item := ev.FindByKey(key)
+			if item == nil {
+				return false, nil
+			}
+			return matchValue(expected, item.Value()), nil
+		},
+	}
+}
+
+// ContainLogEvent succeeds if one event satisfies all the matchers, which will usually
+// be HaveLevel, HaveMessage and HaveField.
+func ContainLogEvent(ms ...types.GomegaMatcher) types.GomegaMatcher {
+	return &eventMatcher{
+		desc:  "contain an event that matches all of\n" + describeAll(ms),
+		match: allOf(ms),
+	}
+}
+
+// HaveLoggedInOrder succeeds if, for each matcher in turn, an event matches it that was
+// logged after the event that matched the previous matcher. Other events may come between.
+func HaveLoggedInOrder(ms ...types.GomegaMatcher) types.GomegaMatcher {
+	return &orderMatcher{ms: ms}
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// eventMatcher applies a test to each event in turn.
+type eventMatcher struct {
+	desc  string
+	match func(ev *testlogger.TestLogEvent) (bool, error)
+}
+
+func (m *eventMatcher) Match(actual interface{}) (bool, error) {
+	evs, err := events(actual)
+	if err != nil {
+		return false, err
+	}
+
+	for _, ev := range evs {
+		ok, err := m.match(ev)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *eventMatcher) FailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected\n%s\nto %s", show(actual), m.desc)
+}
+
+func (m *eventMatcher) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected\n%s\nnot to %s", show(actual), m.desc)
+}
+
+func (m *eventMatcher) String() string {
+	return m.desc
+}
+
+//-------------------------------------------------------------------------------------------------
+
+type orderMatcher struct {
+	ms []types.GomegaMatcher
+}
+
+func (m *orderMatcher) Match(actual interface{}) (bool, error) {
+	evs, err := events(actual)
+	if err != nil {
+		return false, err
+	}
+
+	i := 0
+	for _, ev := range evs {
+		if i == len(m.ms) {
+			break
+		}
+		ok, err := m.ms[i].Match(ev)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			i++
+		}
+	}
+	return i == len(m.ms), nil
+}
+
+func (m *orderMatcher) FailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected\n%s\nto have logged events in this order\n%s", show(actual), describeAll(m.ms))
+}
+
+func (m *orderMatcher) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected\n%s\nnot to have logged events in this order\n%s", show(actual), describeAll(m.ms))
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// events lists the events to be matched, in the order they were logged.
+func events(actual interface{}) ([]*testlogger.TestLogEvent, error) {
+	switch a := actual.(type) {
+	case *testlogger.TestLogger:
+		return a.Events(), nil
+
+	case *testlogger.TestLogEventList:
+		var evs []*testlogger.TestLogEvent
+		for _, root := range a.ToSlice() {
+			if root.Next != nil {
+				evs = append(evs, root.Next)
+			}
+		}
+		return evs, nil
+
+	case *testlogger.TestLogEvent:
+		if a == nil {
+			return nil, nil
+		}
+		return []*testlogger.TestLogEvent{a}, nil
+	}
+
+	return nil, fmt.Errorf("log event matcher expects a *TestLogger, *TestLogEventList or *TestLogEvent; got\n%s",
+		format.Object(actual, 1))
+}
+
+func allOf(ms []types.GomegaMatcher) func(ev *testlogger.TestLogEvent) (bool, error) {
+	return func(ev *testlogger.TestLogEvent) (bool, error) {
+		for _, m := range ms {
+			ok, err := m.Match(ev)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+}
+
+// matchValue compares an actual value with an expected value or matcher. Values that
+// cannot be compared, such as a string and an int, do not match.
+func matchValue(expected, actual interface{}) bool {
+	m, ok := expected.(types.GomegaMatcher)
+	switch {
+	case ok:
+		// use the matcher as it is
+	case expected == nil:
+		m = gomega.BeNil()
+	case isError(actual):
+		m = gomega.MatchError(expected)
+	default:
+		m = gomega.BeEquivalentTo(expected)
+	}
+
+	matched, err := m.Match(actual)
+	return matched && err == nil
+}
+
+func isError(v interface{}) bool {
+	_, ok := v.(error)
+	return ok
+}
+
+func describe(expected interface{}) string {
+	switch e := expected.(type) {
+	case fmt.Stringer:
+		if _, isMatcher := e.(types.GomegaMatcher); isMatcher {
+			return e.String()
+		}
+	case types.GomegaMatcher:
+		return strings.TrimSpace(format.Object(e, 0))
+	}
+	return fmt.Sprintf("%#v", expected)
+}
+
+func describeAll(ms []types.GomegaMatcher) string {
+	buf := &strings.Builder{}
+	for i, m := range ms {
+		fmt.Fprintf(buf, "    %d: %s\n", i+1, describe(m))
+	}
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+// show lists the captured events, one per line, for failure messages.
+func show(actual interface{}) string {
+	evs, err := events(actual)
+	if err != nil {
+		return format.Object(actual, 1)
+	}
+	if len(evs) == 0 {
+		return "    <no events>"
+	}
+
+	buf := &strings.Builder{}
+	for _, ev := range evs {
+		fmt.Fprintf(buf, "    %-5s %s\n", ev.Level(), ev.String())
+	}
+	return strings.TrimRight(buf.String(), "\n")
+}
@@ -0,0 +1,94 @@
+package matchers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/rickb777/ech0/v3/testlogger"
+	"github.com/rs/zerolog"
+)
+
+func capture() *testlogger.TestLogger {
+	tl := testlogger.New(nil)
+	tl.Info().Str("a", "1").Msg("started")
+	tl.Str("request_id", "r1").Warn().Int("attempt", 2).Msg("retrying")
+	tl.Error().Err(errors.New("e1")).Dict("d", testlogger.Dict().Int("x", 5)).Msg("failed")
+	return tl
+}
+
+func TestHaveLogged(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := capture()
+
+	g.Expect(tl).To(HaveLogged(zerolog.WarnLevel, "retrying", Fields{"attempt": 2, "request_id": "r1"}))
+	g.Expect(tl).To(HaveLogged(zerolog.ErrorLevel, HavePrefix("fail"), Fields{"error": "e1", "d.x": int64(5)}))
+	g.Expect(tl).NotTo(HaveLogged(zerolog.InfoLevel, "retrying"))
+	g.Expect(tl.Warns).To(HaveLogged(zerolog.WarnLevel, "retrying"))
+	g.Expect(tl.LastInfo()).To(HaveLogged(zerolog.InfoLevel, "started", Fields{"a": "1"}))
+	g.Expect(tl.LastInfo()).NotTo(HaveLogged(zerolog.InfoLevel, "started", Fields{"a": 1}))
+}
+
+func TestHaveFieldAndMessage(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := capture()
+
+	g.Expect(tl).To(HaveField("request_id", Not(BeEmpty())))
+	g.Expect(tl.Infos).NotTo(HaveField("request_id", "r1"))
+	g.Expect(tl.LastError()).To(HaveField("error", MatchError("e1")))
+	g.Expect(tl).To(HaveMessage("started"))
+	g.Expect(tl.Errors).NotTo(HaveMessage("started"))
+	g.Expect(tl).To(HaveLevel(zerolog.ErrorLevel))
+}
+
+func TestContainLogEvent(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := capture()
+
+	g.Expect(tl).To(ContainLogEvent(HaveMessage("retrying"), HaveField("attempt", BeNumerically(">", 1))))
+	g.Expect(tl).NotTo(ContainLogEvent(HaveMessage("started"), HaveField("attempt", 2)))
+}
+
+func TestHaveLoggedInOrder(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := capture()
+
+	g.Expect(tl).To(HaveLoggedInOrder(HaveMessage("started"), HaveMessage("failed")))
+	g.Expect(tl).NotTo(HaveLoggedInOrder(HaveMessage("failed"), HaveMessage("started")))
+}
+
+func TestFailureMessage(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := capture()
+
+	m := HaveLogged(zerolog.InfoLevel, "stopped")
+	g.Expect(m.Match(tl)).To(BeFalse())
+	g.Expect(m.FailureMessage(tl)).To(Equal(`Expected
+    info  Str(a, 1).Msg(started)
+    warn  Str(request_id, r1).Int(attempt, 2).Msg(retrying)
+    error Err(error, e1).Dict(d, Int(x, 5)).Msg(failed)
+to have logged an event with level info, message "stopped"`))
+
+	m = HaveLoggedInOrder(HaveMessage("failed"), HaveMessage("started"))
+	g.Expect(m.FailureMessage(tl.Errors)).To(Equal(`Expected
+    error Err(error, e1).Dict(d, Int(x, 5)).Msg(failed)
+to have logged events in this order
+    1: have an event with message "failed"
+    2: have an event with message "started"`))
+
+	_, err := m.Match("foo")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestEventually(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := testlogger.New(nil)
+
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		tl.Warn().Int("attempt", 2).Msg("retrying")
+	}()
+
+	g.Eventually(tl.Sent).Should(HaveLogged(zerolog.WarnLevel, "retrying", Fields{"attempt": 2}))
+}
@@ -0,0 +1,44 @@
+package testlogger
+
+import "github.com/rs/zerolog"
+
+// Option configures a TestLogger.
+type Option func(*TestLogger)
+
+// DefaultLevels are the levels of the events that are captured, unless the
+// Capture option is used.
+var DefaultLevels = []zerolog.Level{zerolog.InfoLevel, zerolog.WarnLevel, zerolog.ErrorLevel, zerolog.PanicLevel, zerolog.FatalLevel}
+
+// Capture sets the levels of the events that are captured, replacing DefaultLevels.
+// Debug and trace events are captured in Debugs; events with no level are
+// captured in Prints.
+func Capture(levels ...zerolog.Level) Option {
+	return func(l *TestLogger) {
+		l.levels = make(map[zerolog.Level]bool, len(levels))
+		for _, lvl := range levels {
+			l.levels[lvl] = true
+		}
+	}
+}
+
+// CaptureAll captures events at all levels.
+func CaptureAll() Option {
+	return Capture(zerolog.TraceLevel, zerolog.DebugLevel, zerolog.InfoLevel, zerolog.WarnLevel,
+		zerolog.ErrorLevel, zerolog.PanicLevel, zerolog.FatalLevel, zerolog.NoLevel)
+}
+
+// OnExit sets the function called after a Fatal event, instead of os.Exit.
+// In tests, ech0.PanicOnExit allows the termination to be detected.
+func OnExit(exit func(code int)) Option {
+	return func(l *TestLogger) {
+		l.exit = exit
+	}
+}
+
+// AllowUnterminated stops NewT from failing the test when an event was captured but
+// Msg or Send was never called (see AssertTerminated).
+func AllowUnterminated() Option {
+	return func(l *TestLogger) {
+		l.allowUnterminated = true
+	}
+}
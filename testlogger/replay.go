@@ -0,0 +1,103 @@
+package testlogger
+
+import (
+	"reflect"
+
+	"github.com/rickb777/ech0/v3"
+)
+
+// MarshalZeroObject adds the captured fields to e, so that any TestLogEvent can be
+// used as a dictionary or an object. A dictionary created by Dict replays the fields
+// that were passed to its real event; other events replay their captured fields.
+func (ev *TestLogEvent) MarshalZeroObject(e ech0.ZeroEvent) {
+	root := ev.root()
+	if obj, ok := root.realEvent.(ech0.ObjectMarshaler); ok {
+		obj.MarshalZeroObject(e)
+		return
+	}
+
+	for item := root.Next; item != nil; item = item.Next {
+		e = item.replay(e)
+	}
+}
+
+// replay adds one captured field to e.
+func (ev *TestLogEvent) replay(e ech0.ZeroEvent) ech0.ZeroEvent {
+	switch ev.Method {
+	case "Msg", "Send":
+		return e
+	case "Dict", "Object":
+		return e.Object(ev.Key, nested(ev.Val))
+	case "Array":
+		sub, _ := ev.Val.(*TestLogEvent)
+		return e.Array(ev.Key, capturedArray{sub})
+	case "Err":
+		err, _ := ev.Val.(error)
+		return e.Err(err)
+	case "Stack":
+		return e.Stack()
+	case "Timestamp":
+		return e.Timestamp()
+	case "Caller", "TimeDiff":
+		return e.Interface(ev.Key, ev.Val) // the original arguments were not captured
+	}
+
+	if result, ok := call(e, ev.Method, ev.Key, ev.Val); ok {
+		return result.(ech0.ZeroEvent)
+	}
+	return e.Interface(ev.Key, ev.Val)
+}
+
+// nested returns the captured fields of a dictionary or object, which may be nil if there were none.
+func nested(val interface{}) ech0.ObjectMarshaler {
+	if sub, ok := val.(*TestLogEvent); ok {
+		return sub
+	}
+	return &TestLogEvent{}
+}
+
+// capturedArray replays the captured elements of an array.
+type capturedArray struct {
+	first *TestLogEvent
+}
+
+func (a capturedArray) MarshalZeroArray(arr ech0.ZeroArray) {
+	for item := a.first; item != nil; item = item.Next {
+		switch item.Method {
+		case "Object":
+			arr = arr.Object(nested(item.Val))
+		case "Err":
+			err, _ := item.Val.(error)
+			arr = arr.Err(err)
+		default:
+			if result, ok := call(arr, item.Method, item.Val); ok {
+				arr = result.(ech0.ZeroArray)
+			} else {
+				arr = arr.Interface(item.Val)
+			}
+		}
+	}
+}
+
+// call invokes the named method of target with the arguments, which must suit its
+// parameters, returning its only result. The result is false if this is not possible.
+func call(target interface{}, method string, args ...interface{}) (interface{}, bool) {
+	m := reflect.ValueOf(target).MethodByName(method)
+	if !m.IsValid() || m.Type().NumIn() != len(args) || m.Type().IsVariadic() || m.Type().NumOut() != 1 {
+		return nil, false
+	}
+
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		param := m.Type().In(i)
+		if arg == nil {
+			in[i] = reflect.Zero(param)
+			continue
+		}
+		in[i] = reflect.ValueOf(arg)
+		if !in[i].Type().AssignableTo(param) {
+			return nil, false
+		}
+	}
+	return m.Call(in)[0].Interface(), true
+}
@@ -1,116 +1,231 @@
 package testlogger
 
 import (
+	"fmt"
 	"github.com/rickb777/ech0/v3"
 	"github.com/rs/zerolog"
 	"io"
 	"os"
+	"reflect"
+	"runtime"
 	"strconv"
+	"strings"
 	"sync"
+	"testing"
+	"time"
 )
 
-// TestLogger captures log messages, organised by level: Infos, Warns, Errors and Panics.
-// It deliberately ignores Debug level messages.
+// TestLogger captures log messages. All holds every captured event in the order they
+// were logged, across all levels and goroutines. Each event is also added to the list for
+// its level: Debugs, Infos, Warns, Errors, Panics, Fatals or Prints (for events with no
+// level). These are separate lists, not views of All, but Discard and Reset keep them in
+// step. By default, Debugs and Prints are not captured; use the Capture option to choose
+// the levels (see DefaultLevels).
 //
-// Note that Fatal will call os.Exit so cannot usefully be tested.
+// Fatal events call os.Exit, unless the OnExit option is used.
 type TestLogger struct {
 	realLogger ech0.Zero
+	All        *TestLogEventList
+	Debugs     *TestLogEventList
 	Infos      *TestLogEventList
 	Warns      *TestLogEventList
 	Errors     *TestLogEventList
 	Panics     *TestLogEventList
-	mu         *sync.Mutex
-	// note that debug messages are deliberately ignored
-	// and fatal messages cannot be captured
+	Fatals     *TestLogEventList
+	Prints     *TestLogEventList
+	levels     map[zerolog.Level]bool
+	exit       func(code int)
+	level      zerolog.Level
+	context    []field
+	order      *order // shared with child loggers
+
+	allowUnterminated bool
 }
 
-var _ ech0.Zero = &TestLogger{}
+// order numbers the captured events and notifies waiters when they are sent.
+// It is shared by a logger and its children.
+type order struct {
+	mu      sync.Mutex
+	seq     uint64
+	changed chan struct{} // closed when an event is sent; nil until someone waits
+}
 
-func New(realLogger ech0.Zero) *TestLogger {
-	return &TestLogger{
+// field is a context field of a child logger.
+type field struct {
+	method, key string
+	val         interface{}
+}
+
+var (
+	_ ech0.Zero       = &TestLogger{}
+	_ ech0.Terminator = &TestLogger{}
+)
+
+// New creates a TestLogger. If realLogger is not nil, events are also passed on to it.
+func New(realLogger ech0.Zero, opts ...Option) *TestLogger {
+	if realLogger == nil {
+		realLogger = ech0.Nop()
+	}
+	l := &TestLogger{
 		realLogger: realLogger,
+		All:        NewTestLogEventList(),
+		Debugs:     NewTestLogEventList(),
 		Infos:      NewTestLogEventList(),
 		Warns:      NewTestLogEventList(),
 		Errors:     NewTestLogEventList(),
 		Panics:     NewTestLogEventList(),
-		mu:         &sync.Mutex{},
+		Fatals:     NewTestLogEventList(),
+		Prints:     NewTestLogEventList(),
+		level:      zerolog.TraceLevel,
+		order:      &order{},
 	}
+	Capture(DefaultLevels...)(l)
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 // NewWithConsoleLogger creates a new test logger with a wrapped console logger.
-func NewWithConsoleLogger() *TestLogger {
-	return New(ech0.Wrap(zerolog.New(zerolog.NewConsoleWriter())))
+func NewWithConsoleLogger(opts ...Option) *TestLogger {
+	return New(ech0.Wrap(zerolog.New(zerolog.NewConsoleWriter())), opts...)
 }
 
-func (l *TestLogger) Log() ech0.ZeroEvent {
-	var ze ech0.ZeroEvent
-	if l.realLogger != nil {
-		ze = l.realLogger.Log()
+// NewT creates a TestLogger for the test t. When the test has finished, the test fails
+// if any captured event was never terminated by Msg or Send, unless the AllowUnterminated
+// option is used (see AssertTerminated). Then all the captured events are printed in order
+// using t.Log, if the test failed or if the tests are running in verbose mode.
+//
+// The logger can also be used as the backend for ech0.New.
+func NewT(t testing.TB, opts ...Option) *TestLogger {
+	l := New(nil, opts...)
+	t.Cleanup(func() {
+		if !l.allowUnterminated {
+			AssertTerminated(t, l)
+		}
+		if t.Failed() || testing.Verbose() {
+			t.Log(l.dump())
+		}
+	})
+	return l
+}
+
+// dump lists all the captured events, one per line.
+func (l *TestLogger) dump() string {
+	events := l.Events()
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "captured %d log event(s)", len(events))
+	for _, ev := range events {
+		fmt.Fprintf(buf, "\n    %-5s %s", ev.Level(), ev)
 	}
-	return &TestLogEvent{realEvent: ze} // will be discarded after use
+	return buf.String()
+}
+
+func (l *TestLogger) Log() ech0.ZeroEvent {
+	ze := l.realLogger.Log()
+	return l.capture(zerolog.NoLevel, l.Prints, ze, nil)
 }
 
 func (l *TestLogger) Debug() ech0.ZeroEvent {
-	var ze ech0.ZeroEvent
-	if l.realLogger != nil {
-		ze = l.realLogger.Debug()
-	}
-	return &TestLogEvent{realEvent: ze} // will be discarded after use
+	ze := l.realLogger.Debug()
+	return l.capture(zerolog.DebugLevel, l.Debugs, ze, nil)
 }
 
 func (l *TestLogger) Info() ech0.ZeroEvent {
-	var ze ech0.ZeroEvent
-	if l.realLogger != nil {
-		ze = l.realLogger.Info()
-	}
-
-	first := &TestLogEvent{realEvent: ze}
-	l.Infos.Add(first)
-	return first
+	ze := l.realLogger.Info()
+	return l.capture(zerolog.InfoLevel, l.Infos, ze, nil)
 }
 
 func (l *TestLogger) Warn() ech0.ZeroEvent {
-	var ze ech0.ZeroEvent
-	if l.realLogger != nil {
-		ze = l.realLogger.Warn()
-	}
-
-	first := &TestLogEvent{realEvent: ze}
-	l.Warns.Add(first)
-	return first
+	ze := l.realLogger.Warn()
+	return l.capture(zerolog.WarnLevel, l.Warns, ze, nil)
 }
 
 func (l *TestLogger) Error() ech0.ZeroEvent {
-	var ze ech0.ZeroEvent
-	if l.realLogger != nil {
-		ze = l.realLogger.Error()
-	}
-
-	first := &TestLogEvent{realEvent: ze}
-	l.Errors.Add(first)
-	return first
+	ze := l.realLogger.Error()
+	return l.capture(zerolog.ErrorLevel, l.Errors, ze, nil)
 }
 
 func (l *TestLogger) Panic() ech0.ZeroEvent {
-	var ze ech0.ZeroEvent
-	if l.realLogger != nil {
-		ze = l.realLogger.Panic()
+	ze := l.realLogger.Panic()
+	return l.capture(zerolog.PanicLevel, l.Panics, ze, func(s string) { panic(s) })
+}
+
+// Fatal starts a new message with fatal level. The exit function is called by the Msg
+// method; by default, this is os.Exit, which terminates the program immediately.
+// Use the OnExit option, e.g. with ech0.PanicOnExit, to test fatal events.
+func (l *TestLogger) Fatal() ech0.ZeroEvent {
+	ze := l.realLogger.WithLevel(zerolog.FatalLevel) // the real logger must not exit
+	return l.capture(zerolog.FatalLevel, l.Fatals, ze, func(string) { l.Terminate(1) })
+}
+
+// Terminate ends the program after a Fatal event, using the OnExit function if one was set,
+// or os.Exit otherwise. This allows ech0.Tee to use the OnExit function too.
+func (l *TestLogger) Terminate(code int) {
+	if l.exit != nil {
+		l.exit(code)
+	} else {
+		os.Exit(code)
+	}
+}
+
+// capture adds a new event to the list, if its level is captured. Otherwise, the event
+// will be discarded after use. The event starts with the logger's context fields.
+// Events below the logger's level are disabled.
+func (l *TestLogger) capture(level zerolog.Level, list *TestLogEventList, ze ech0.ZeroEvent, done func(string)) ech0.ZeroEvent {
+	if level < l.level {
+		return ech0.Disabled()
+	}
+	if !l.levels[level] {
+		return &TestLogEvent{realEvent: ze, done: done, level: level}
 	}
 
-	first := &TestLogEvent{realEvent: ze, done: func(s string) { panic(s) }}
-	l.Panics.Add(first)
+	first := &TestLogEvent{realEvent: ze, done: done, owners: []*TestLogEventList{l.All, list}, level: level, callsite: callsite(), order: l.order}
+	for _, f := range l.context {
+		first.add(nil, f.method, f.key, f.val)
+	}
+
+	// numbering and adding together keeps All in sequence when events are logged concurrently
+	l.order.mu.Lock()
+	defer l.order.mu.Unlock()
+	l.order.seq++
+	first.seq = l.order.seq
+	first.at = time.Now()
+	l.All.Add(first)
+	list.Add(first)
 	return first
 }
 
-// Fatal starts a new message with fatal level. The os.Exit(1) function
-// is called by the Msg method, which terminates the program immediately.
-// Therefore, this should be avoided during testing.
-func (l *TestLogger) Fatal() ech0.ZeroEvent {
-	var ze ech0.ZeroEvent
-	if l.realLogger != nil {
-		ze = l.realLogger.Fatal()
+var (
+	ech0Package       = reflect.TypeOf(ech0.Log{}).PkgPath() + "."
+	testloggerPackage = reflect.TypeOf(TestLogger{}).PkgPath() + "."
+)
+
+// callsite finds the file:line where an event was created, formatted by zerolog.CallerMarshalFunc.
+// This is the first caller outside ech0 and testlogger, apart from their tests.
+func callsite() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs) // skips runtime.Callers, callsite and capture
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		internal := strings.HasPrefix(f.Function, ech0Package) || strings.HasPrefix(f.Function, testloggerPackage)
+		if !internal || strings.HasSuffix(f.File, "_test.go") {
+			return zerolog.CallerMarshalFunc(f.File, f.Line)
+		}
+		if !more {
+			return ""
+		}
 	}
-	return &TestLogEvent{realEvent: ze, done: func(string) { os.Exit(1) }}
+}
+
+// child returns a copy of l with more context fields. It shares the captured events with l.
+func (l *TestLogger) child(real ech0.Zero, fields ...field) *TestLogger {
+	c := *l
+	c.realLogger = real
+	n := len(l.context)
+	c.context = append(l.context[:n:n], fields...)
+	return &c
 }
 
 func (l *TestLogger) Err(err error) ech0.ZeroEvent {
@@ -122,6 +237,8 @@ func (l *TestLogger) Err(err error) ech0.ZeroEvent {
 
 func (l *TestLogger) WithLevel(level zerolog.Level) ech0.ZeroEvent {
 	switch level {
+	case zerolog.TraceLevel:
+		return l.capture(zerolog.TraceLevel, l.Debugs, l.realLogger.WithLevel(level), nil)
 	case zerolog.DebugLevel:
 		return l.Debug()
 	case zerolog.InfoLevel:
@@ -131,78 +248,70 @@ func (l *TestLogger) WithLevel(level zerolog.Level) ech0.ZeroEvent {
 	case zerolog.ErrorLevel:
 		return l.Error()
 
-	// zerolog has odd behaviour for fatal and panic that avoids
-	// the normal termination behaviour; it's not supported here.
-	//case zerolog.FatalLevel:
-	//	return l.newEvent(zerolog.FatalLevel, nil)
+	// as with zerolog, a fatal event does not terminate the program
+	case zerolog.FatalLevel:
+		return l.capture(zerolog.FatalLevel, l.Fatals, l.realLogger.WithLevel(level), nil)
 	case zerolog.PanicLevel:
 		return l.Panic()
 	case zerolog.NoLevel:
 		return l.Log()
 
 	case zerolog.Disabled:
-		return nil
+		return ech0.Disabled()
 	default:
 		panic("zerolog: WithLevel(): invalid level: " + strconv.Itoa(int(level)))
 	}
 }
 
+// Output creates a child logger with the real logger's output set to w.
 func (l *TestLogger) Output(w io.Writer) ech0.Zero {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	if l.realLogger != nil {
-		l.realLogger = l.realLogger.Output(w)
-	}
-	return l
+	return l.child(l.realLogger.Output(w))
 }
 
+// Level creates a child logger with the minimum accepted level set to lvl.
+// Events below this level are neither captured nor passed on to the real logger.
 func (l *TestLogger) Level(lvl zerolog.Level) ech0.Zero {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	if l.realLogger != nil {
-		l.realLogger = l.realLogger.Level(lvl)
-	}
-	return l
+	c := l.child(l.realLogger.Level(lvl))
+	c.level = lvl
+	return c
 }
 
+// ErrorStack creates a child logger whose real logger uses m for ZeroEvent.Stack.
+func (l *TestLogger) ErrorStack(m ech0.StackMarshaler) ech0.Zero {
+	return l.child(l.realLogger.ErrorStack(m))
+}
+
+// Str creates a child logger with the field key and with val as a string to the logger context.
 func (l *TestLogger) Str(key, val string) ech0.Zero {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	if l.realLogger != nil {
-		l.realLogger = l.realLogger.Str(key, val)
-	}
-	return l
+	return l.child(l.realLogger.Str(key, val), field{method: "Str", key: key, val: val})
 }
 
+// Int creates a child logger with the field key and with val as an int to the logger context.
 func (l *TestLogger) Int(key string, val int) ech0.Zero {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	if l.realLogger != nil {
-		l.realLogger = l.realLogger.Int(key, val)
-	}
-	return l
+	return l.child(l.realLogger.Int(key, val), field{method: "Int", key: key, val: val})
 }
 
+// Bool creates a child logger with the field key and with val as a bool to the logger context.
+func (l *TestLogger) Bool(key string, val bool) ech0.Zero {
+	return l.child(l.realLogger.Bool(key, val), field{method: "Bool", key: key, val: val})
+}
+
+// RawJSON creates a child logger with the field key with val as already encoded JSON to context.
 func (l *TestLogger) RawJSON(key string, val []byte) ech0.Zero {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	if l.realLogger != nil {
-		l.realLogger = l.realLogger.RawJSON(key, val)
-	}
-	return l
+	return l.child(l.realLogger.RawJSON(key, val), field{method: "RawJSON", key: key, val: val})
 }
 
+// Timestamp creates a child logger that adds the current time to each event.
 func (l *TestLogger) Timestamp() ech0.Zero {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	if l.realLogger != nil {
-		l.realLogger = l.realLogger.Timestamp()
-	}
-	return l
+	return l.child(l.realLogger.Timestamp(), field{method: "Timestamp"})
 }
 
 //-------------------------------------------------------------------------------------------------
 
+func (l *TestLogger) LastDebug() *TestLogEvent {
+	return l.Debugs.Last()
+}
+
 func (l *TestLogger) LastInfo() *TestLogEvent {
 	return l.Infos.Last()
 }
@@ -215,9 +324,36 @@ func (l *TestLogger) LastError() *TestLogEvent {
 	return l.Errors.Last()
 }
 
+func (l *TestLogger) LastFatal() *TestLogEvent {
+	return l.Fatals.Last()
+}
+
+func (l *TestLogger) LastPrint() *TestLogEvent {
+	return l.Prints.Last()
+}
+
+// Events returns all the captured events, at every level, in the order they were logged.
+// As with TestLogEventList.First, each event is the first item after the level setting.
+func (l *TestLogger) Events() []*TestLogEvent {
+	roots := l.All.ToSlice()
+	events := make([]*TestLogEvent, 0, len(roots))
+	for _, r := range roots {
+		if r.Next != nil {
+			events = append(events, r.Next)
+		}
+	}
+	return events
+}
+
 func (l *TestLogger) Reset() {
+	l.order.mu.Lock()
+	defer l.order.mu.Unlock()
+	l.All.Clear()
+	l.Debugs.Clear()
 	l.Infos.Clear()
 	l.Warns.Clear()
 	l.Errors.Clear()
 	l.Panics.Clear()
+	l.Fatals.Clear()
+	l.Prints.Clear()
 }
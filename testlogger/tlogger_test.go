@@ -1,10 +1,18 @@
 package testlogger
 
 import (
+	"errors"
+	"fmt"
 	. "github.com/onsi/gomega"
 	"github.com/rickb777/ech0/v3"
 	"github.com/rs/zerolog"
+	"io/ioutil"
+	"net"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func Test1(t *testing.T) {
@@ -26,14 +34,435 @@ func Test1(t *testing.T) {
 	g.Expect(tl.Warns.Drop(1).First().FindByKey("c").Value()).To(Equal(3))
 	g.Expect(tl.Warns.DropLast(1).Last().FindByKey("").Value()).To(Equal("m4"))
 	g.Expect(tl.LastWarn().FindByKey("").Value()).To(Equal("m5"))
-	g.Expect(tl.LastWarn().FindByKey("a").Value()).To(BeNil())
+	g.Expect(tl.LastWarn().FindByKey("a").Value()).To(Equal(101))
+	g.Expect(tl.Warns.First().FindByKey("a")).To(BeNil())
 
 	g.Expect(tl.Warns.First().String()).To(Equal("Int(b, 2).Msg(m3)"))
-	g.Expect(tl.Warns.Drop(1).First().String()).To(Equal("Int(c, 3).Msg(m4)"))
-	g.Expect(tl.Warns.Drop(2).First().String()).To(Equal("Int(d, 4).Msg(m5)"))
+	g.Expect(tl.Warns.Drop(1).First().String()).To(Equal("Int(a, 100).Int(c, 3).Msg(m4)"))
+	g.Expect(tl.Warns.Drop(2).First().String()).To(Equal("Int(a, 101).Int(d, 4).Msg(m5)"))
 
 	tl.Reset()
 
 	g.Expect(tl.Warns.IsEmpty()).To(BeTrue())
 	g.Expect(tl.LastWarn()).To(BeNil())
 }
+
+func TestWith(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	tl := New(ech0.Wrap(zerolog.New(buf)))
+
+	z := tl.With().Str("a", "1").Bool("b", true).Logger()
+	z.Warn().Int("c", 3).Msg("m1")
+
+	g.Expect(buf.String()).To(Equal(`{"level":"warn","a":"1","b":true,"c":3,"message":"m1"}` + "\n"))
+	g.Expect(tl.Warns.Len()).To(Equal(1))
+	g.Expect(tl.LastWarn().String()).To(Equal("Str(a, 1).Bool(b, true).Int(c, 3).Msg(m1)"))
+
+	New(nil).With().Str("a", "1").Logger().Info().Msg("m2")
+}
+
+func TestChildLoggers(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	tl := New(ech0.Wrap(zerolog.New(buf)))
+
+	req := tl.Str("request_id", "r1")
+	sub := req.With().Fields(map[string]interface{}{"y": 2, "x": 1}).Dict("d", ech0.Dict().Int("e", 5)).Logger()
+	quiet := req.Level(zerolog.WarnLevel)
+
+	tl.Info().Msg("m1")
+	req.Info().Msg("m2")
+	sub.Info().Send()
+	quiet.Info().Msg("m3")
+	quiet.Warn().Msg("m4")
+
+	// the parent is unchanged
+	g.Expect(tl.Infos.First().String()).To(Equal("Msg(m1)"))
+
+	// the children share the captured events
+	g.Expect(tl.Infos.Len()).To(Equal(3))
+	g.Expect(tl.Infos.Drop(1).First().String()).To(Equal("Str(request_id, r1).Msg(m2)"))
+	g.Expect(tl.LastInfo().FindByKey("request_id").Value()).To(Equal("r1"))
+	g.Expect(tl.LastInfo().FindByKey("x").Value()).To(Equal(1))
+	g.Expect(tl.LastInfo().FindByKey("d.e").Value()).To(Equal(5))
+	g.Expect(tl.LastWarn().String()).To(Equal("Str(request_id, r1).Msg(m4)"))
+
+	g.Expect(buf.String()).To(Equal(`{"level":"info","message":"m1"}` + "\n" +
+		`{"level":"info","request_id":"r1","message":"m2"}` + "\n" +
+		`{"level":"info","request_id":"r1","x":1,"y":2,"d":{"e":5}}` + "\n" +
+		`{"level":"warn","request_id":"r1","message":"m4"}` + "\n"))
+}
+
+func TestTypedValues(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := New(nil)
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	e1 := errors.New("x")
+
+	tl.Info().
+		Float64("f", 1.5).
+		Int16("i", 16).
+		Uints32("u", []uint32{1}).
+		IPAddr("ip", net.IPv4(1, 2, 3, 4)).
+		TimeDiff("td", t0, t0.Add(time.Second)).
+		Errs("es", []error{e1}).
+		Msg("m")
+
+	ev := tl.LastInfo()
+	g.Expect(ev.FindByKey("f").Value()).To(Equal(1.5))
+	g.Expect(ev.FindByKey("i").Value()).To(Equal(int16(16)))
+	g.Expect(ev.FindByKey("u").Value()).To(Equal([]uint32{1}))
+	g.Expect(ev.FindByKey("ip").Value()).To(Equal(net.IPv4(1, 2, 3, 4)))
+	g.Expect(ev.FindByKey("td").Value()).To(Equal(time.Duration(0)))
+	g.Expect(ev.FindByKey("es").Value()).To(Equal([]error{e1}))
+	g.Expect(ev.FindByKey("td").Method).To(Equal("TimeDiff"))
+}
+
+type user struct {
+	name string
+	age  int
+}
+
+func (u user) MarshalZeroObject(e ech0.ZeroEvent) {
+	e.Str("name", u.name)
+	e.Int("age", u.age)
+}
+
+func TestObjectAndArray(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	tl := New(ech0.Wrap(zerolog.New(buf)))
+
+	tl.Info().
+		Object("u", user{"Ann", 30}).
+		EmbedObject(user{"Bob", 40}).
+		Array("a", ech0.Arr().Str("x").Object(user{"Cy", 50})).
+		Msg("m")
+
+	g.Expect(buf.String()).To(Equal(`{"level":"info","u":{"name":"Ann","age":30},"name":"Bob","age":40,` +
+		`"a":["x",{"name":"Cy","age":50}],"message":"m"}` + "\n"))
+
+	ev := tl.LastInfo()
+	g.Expect(ev.String()).To(HavePrefix("Object(u, Str(name, Ann).Int(age, 30)).Str(name, Bob).Int(age, 40).Array(a, "))
+
+	u := ev.FindByKey("u").Value().(*TestLogEvent)
+	g.Expect(u.FindByKey("age").Value()).To(Equal(30))
+	g.Expect(ev.FindByKey("name").Value()).To(Equal("Bob"))
+
+	a := ev.FindByKey("a").Value().(*TestLogEvent)
+	g.Expect(a.FindByKey("0").Value()).To(Equal("x"))
+	g.Expect(a.FindByKey("1").Value().(*TestLogEvent).FindByKey("name").Value()).To(Equal("Cy"))
+}
+
+func TestDiscardAndFunc(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := New(nil)
+
+	tl.Warn().Str("a", "1").Msg("kept")
+	ev := tl.Warn().Str("b", "2")
+	g.Expect(ev.Enabled()).To(BeTrue())
+	ev.Discard().Msg("dropped")
+	g.Expect(ev.Enabled()).To(BeFalse())
+
+	tl.Warn().Func(func(e ech0.ZeroEvent) { e.Int("c", 3) }).Func(func(e ech0.ZeroEvent) { e.Int("d", 4) }).Msg("func")
+
+	g.Expect(tl.Warns.Len()).To(Equal(2))
+	g.Expect(tl.Warns.First().String()).To(Equal("Str(a, 1).Msg(kept)"))
+	g.Expect(tl.LastWarn().String()).To(Equal("Int(c, 3).Int(d, 4).Msg(func)"))
+
+	// a discarded panic event does not panic
+	tl.Panic().Discard().Msg("no panic")
+	g.Expect(tl.Panics.IsEmpty()).To(BeTrue())
+}
+
+func TestStackAndCaller(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	tl := New(ech0.Wrap(zerolog.New(buf))).ErrorStack(func(err error) interface{} { return "s" }).(*TestLogger)
+
+	tl.Error().Stack().Err(errors.New("e1")).Caller().Msg("m")
+
+	ev := tl.LastError()
+	g.Expect(ev.FindByKey("error").Value()).To(MatchError("e1"))
+	g.Expect(ev.FindByKey(zerolog.CallerFieldName).Value()).To(ContainSubstring("tlogger_test.go:"))
+	g.Expect(buf.String()).To(HavePrefix(`{"level":"error","error":"e1","stack":"s","caller":"`))
+	g.Expect(buf.String()).To(ContainSubstring(`tlogger_test.go:`))
+}
+
+func TestWithLevelDisabled(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := New(nil)
+
+	ev := tl.WithLevel(zerolog.Disabled)
+	g.Expect(ev.Enabled()).To(BeFalse())
+	ev.Str("a", "1").Int("b", 2).Msg("m")
+
+	tl.Info().Str("a", "1").Msg("m")
+	g.Expect(tl.Infos.Len()).To(Equal(1))
+}
+
+func TestDict(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	tl := New(ech0.Wrap(zerolog.New(buf)))
+
+	tl.Info().
+		Dict("d", Dict().Str("a", "1").Dict("e", Dict().Int("f", 6))).
+		Dict("z", ech0.Dict().Int("g", 7)).
+		Array("us", ech0.Arr().Object(user{"Ann", 30}).Object(user{"Bob", 40})).
+		Msg("m")
+
+	g.Expect(buf.String()).To(Equal(`{"level":"info","d":{"a":"1","e":{"f":6}},"z":{"g":7},` +
+		`"us":[{"name":"Ann","age":30},{"name":"Bob","age":40}],"message":"m"}` + "\n"))
+
+	ev := tl.LastInfo()
+	g.Expect(ev.FindByKey("d.a").Value()).To(Equal("1"))
+	g.Expect(ev.FindByKey("d.e.f").Value()).To(Equal(6))
+	g.Expect(ev.FindByKey("z.g").Value()).To(Equal(7))
+	g.Expect(ev.FindByKey("us.1.name").Value()).To(Equal("Bob"))
+	g.Expect(ev.FindByKey("d.x")).To(BeNil())
+	g.Expect(ev.FindByKey("d.a.x")).To(BeNil())
+}
+
+func TestTee(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	tl := New(nil)
+	z := ech0.Tee(ech0.Wrap(zerolog.New(buf)).Level(zerolog.ErrorLevel), tl)
+
+	z.Warn().Int("a", 1).Msg("m1")
+	z.Error().Int("b", 2).Msg("m2")
+
+	g.Expect(buf.String()).To(Equal(`{"level":"error","b":2,"message":"m2"}` + "\n"))
+	g.Expect(tl.LastWarn().String()).To(Equal("Int(a, 1).Msg(m1)"))
+	g.Expect(tl.LastError().String()).To(Equal("Int(b, 2).Msg(m2)"))
+}
+
+func TestCaptureLevels(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tl := New(nil)
+	l := ech0.New(nil, "", tl)
+	l.Debug("d1")
+	l.Print("p1")
+	l.Info("i1")
+	g.Expect(tl.Debugs.IsEmpty()).To(BeTrue())
+	g.Expect(tl.Prints.IsEmpty()).To(BeTrue())
+	g.Expect(tl.Infos.Len()).To(Equal(1))
+
+	tl = New(nil, CaptureAll())
+	l = ech0.New(nil, "", tl)
+	l.Debug("d1")
+	tl.WithLevel(zerolog.TraceLevel).Msg("t1")
+	l.Print("p1")
+	l.Info("i1")
+	g.Expect(tl.Debugs.Len()).To(Equal(2))
+	g.Expect(tl.LastDebug().String()).To(Equal("Msg(t1)"))
+	g.Expect(tl.LastPrint().String()).To(Equal("Str(level, -).Msg(p1)"))
+	g.Expect(tl.Infos.Len()).To(Equal(1))
+
+	tl = New(nil, Capture(zerolog.ErrorLevel))
+	tl.Info().Msg("i1")
+	tl.Error().Msg("e1")
+	g.Expect(tl.Infos.IsEmpty()).To(BeTrue())
+	g.Expect(tl.Errors.Len()).To(Equal(1))
+
+	// panics still happen when they are not captured
+	g.Expect(func() { tl.Panic().Msg("p2") }).To(PanicWith("p2"))
+	g.Expect(tl.Panics.IsEmpty()).To(BeTrue())
+}
+
+func TestFatal(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := New(nil, OnExit(ech0.PanicOnExit))
+
+	g.Expect(func() { tl.Fatal().Int("a", 1).Msg("bye") }).To(PanicWith(ech0.Exit{Code: 1}))
+	g.Expect(tl.Fatals.Len()).To(Equal(1))
+	g.Expect(tl.LastFatal().String()).To(Equal("Int(a, 1).Msg(bye)"))
+
+	// via the echo logger
+	l := ech0.New(nil, "", tl)
+	g.Expect(func() { l.Fatal("bye again") }).To(PanicWith(ech0.Exit{Code: 1}))
+	g.Expect(tl.Fatals.Len()).To(Equal(2))
+	g.Expect(tl.LastFatal().String()).To(Equal("Msg(bye again)"))
+}
+
+func TestNewT(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	r := &recorder{}
+	tl := NewT(r)
+	lg := ech0.New(ioutil.Discard, "", tl)
+	lg.Info("hello")
+	tl.Warn().Int("a", 1).Msg("careful")
+	r.finish()
+
+	g.Expect(tl.LastInfo().Message()).To(Equal("hello"))
+	if testing.Verbose() {
+		g.Expect(r.logs).To(HaveLen(1))
+	} else {
+		g.Expect(r.logs).To(BeEmpty())
+	}
+
+	r = &recorder{}
+	tl = NewT(r)
+	ech0.New(ioutil.Discard, "", tl).Info("hello")
+	tl.Warn().Int("a", 1).Msg("careful")
+	r.Errorf("failed")
+	r.finish()
+
+	g.Expect(r.logs).To(Equal([]string{"captured 2 log event(s)\n    info  Msg(hello)\n    warn  Int(a, 1).Msg(careful)"}))
+}
+
+func TestAll(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := New(nil)
+	before := time.Now()
+
+	tl.Warn().Str("a", "x").Msg("A")
+	tl.Str("request_id", "r1").Error().Dict("d", Dict().Int("e", 1)).Strs("s", []string{"p"}).Msg("B")
+	tl.Info().Msg("C")
+	tl.Info().Discard().Msg("D")
+
+	g.Expect(tl.All.Len()).To(Equal(3))
+	g.Expect(tl.Warns.Len()).To(Equal(1))
+	g.Expect(tl.Errors.Len()).To(Equal(1))
+	g.Expect(tl.Infos.Len()).To(Equal(1))
+
+	a, b, c := tl.All.Get(0), tl.All.Get(1), tl.All.Get(2)
+	g.Expect(a).To(BeIdenticalTo(tl.Warns.Get(0)))
+	g.Expect(b).To(BeIdenticalTo(tl.Errors.Get(0)))
+	g.Expect([]uint64{a.Seq(), b.Seq(), c.Seq()}).To(Equal([]uint64{1, 2, 3}))
+	g.Expect([]zerolog.Level{a.Level(), b.Level(), c.Level()}).To(Equal([]zerolog.Level{zerolog.WarnLevel, zerolog.ErrorLevel, zerolog.InfoLevel}))
+	g.Expect(b.Message()).To(Equal("B"))
+	g.Expect(b.CapturedAt()).To(BeTemporally(">=", before))
+	g.Expect(b.CapturedAt()).To(BeTemporally(">=", a.CapturedAt()))
+
+	g.Expect(b.Next.Fields()).To(Equal(Fields{"request_id": "r1", "d": Fields{"e": 1}, "s": []string{"p"}}))
+	g.Expect(c.Fields()).To(BeEmpty())
+
+	tl.Reset()
+	g.Expect(tl.All.IsEmpty()).To(BeTrue())
+}
+
+func TestAll_concurrent(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := New(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				tl.Int("g", i).Warn().Int("j", j).Msg("m")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	g.Expect(tl.All.Len()).To(Equal(100))
+	for i, ev := range tl.All.ToSlice() {
+		g.Expect(ev.Seq()).To(BeEquivalentTo(i + 1))
+	}
+}
+
+func TestAccessors(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := New(nil)
+	e1 := errors.New("e1")
+
+	tl.Str("a", "x").Warn().Int64("n", 5).Err(e1).Dur("d", time.Second).Stringer("ip", net.IPv4(1, 2, 3, 4)).
+		Dict("sub", Dict().Uint8("u", 7)).Int("n", 6).Msg("m1")
+	unsent := tl.Info().Str("b", "y")
+
+	ev := tl.LastWarn()
+	g.Expect(ev.Message()).To(Equal("m1"))
+	g.Expect(ev.Level()).To(Equal(zerolog.WarnLevel))
+	g.Expect(ev.Keys()).To(Equal([]string{"a", "n", "error", "d", "ip", "sub"}))
+	g.Expect(ev.Fields()).To(HaveLen(6))
+	g.Expect(ev.Fields()).To(HaveKeyWithValue("n", int64(5)))
+	g.Expect(ev.HasKey("sub.u")).To(BeTrue())
+	g.Expect(ev.HasKey("c")).To(BeFalse())
+	g.Expect(ev.HasKey("")).To(BeFalse())
+
+	v, ok := ev.Get("a")
+	g.Expect(v).To(Equal("x"))
+	g.Expect(ok).To(BeTrue())
+	_, ok = ev.Get("c")
+	g.Expect(ok).To(BeFalse())
+
+	n, ok := ev.GetInt("n")
+	g.Expect(n).To(Equal(5))
+	g.Expect(ok).To(BeTrue())
+	n, ok = ev.GetInt("sub.u")
+	g.Expect(n).To(Equal(7))
+	g.Expect(ok).To(BeTrue())
+	_, ok = ev.GetInt("a")
+	g.Expect(ok).To(BeFalse())
+
+	s, ok := ev.GetStr("ip")
+	g.Expect(s).To(Equal("1.2.3.4"))
+	g.Expect(ok).To(BeTrue())
+	_, ok = ev.GetStr("n")
+	g.Expect(ok).To(BeFalse())
+
+	d, ok := ev.GetDur("d")
+	g.Expect(d).To(Equal(time.Second))
+	g.Expect(ok).To(BeTrue())
+
+	g.Expect(ev.GetErr("error")).To(BeIdenticalTo(e1))
+	g.Expect(ev.GetErr("a")).To(BeNil())
+
+	g.Expect(ev.IsTerminated()).To(BeTrue())
+	g.Expect(unsent.(*TestLogEvent).IsTerminated()).To(BeFalse())
+	g.Expect(tl.LastInfo().IsTerminated()).To(BeFalse())
+
+	tl.Error().Send()
+	g.Expect(tl.Errors.Get(0).IsTerminated()).To(BeTrue())
+}
+
+func TestCallsite(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := New(nil)
+
+	ech0.New(ioutil.Discard, "", tl).Info("hello")
+	_, file, line, _ := runtime.Caller(0)
+
+	g.Expect(tl.LastInfo().Callsite()).To(Equal(fmt.Sprintf("%s:%d", file, line-1)))
+}
+
+func TestTee_fatal(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	tl := New(nil, OnExit(ech0.PanicOnExit))
+	z := ech0.Tee(ech0.Wrap(zerolog.New(buf)), tl)
+
+	g.Expect(func() { z.Fatal().Int("a", 1).Msg("f1") }).To(PanicWith(ech0.Exit{Code: 1}))
+	g.Expect(buf.String()).To(Equal(`{"level":"fatal","a":1,"message":"f1"}` + "\n"))
+	g.Expect(tl.LastFatal().String()).To(Equal("Int(a, 1).Msg(f1)"))
+
+	// the exit function is used by child loggers, even when no logger accepts fatal events
+	g.Expect(func() { z.Str("s", "x").Level(zerolog.Disabled).Fatal().Msg("f2") }).To(PanicWith(ech0.Exit{Code: 1}))
+	g.Expect(func() { z.With().Int("c", 3).Logger().Fatal().Send() }).To(PanicWith(ech0.Exit{Code: 1}))
+	g.Expect(tl.Fatals.Len()).To(Equal(2))
+}
+
+func TestDict_events(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	tl := New(ech0.Wrap(zerolog.New(buf)))
+	uncaptured := New(nil, Capture())
+
+	d1 := uncaptured.Info().Str("a", "1").Int("n", 2).Err(errors.New("e1")).
+		Array("arr", ech0.Arr().Str("x").Object(user{"Ann", 30}))
+	d2 := ech0.Tee(ech0.Wrap(zerolog.New(ioutil.Discard)), uncaptured).Info().Str("b", "2")
+
+	tl.Info().Dict("d1", d1).Dict("d2", d2).Msg("m")
+
+	g.Expect(buf.String()).To(Equal(`{"level":"info","d1":{"a":"1","n":2,"error":"e1","arr":["x",{"name":"Ann","age":30}]},` +
+		`"d2":{"b":"2"},"message":"m"}` + "\n"))
+	g.Expect(tl.LastInfo().FindByKey("d1.n").Value()).To(Equal(2))
+	g.Expect(tl.LastInfo().FindByKey("d2.b").Value()).To(Equal("2"))
+}
@@ -0,0 +1,78 @@
+package testlogger
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// markSent records that Msg or Send has been called, and wakes up any goroutines
+// in WaitFor if the event was captured.
+func (ev *TestLogEvent) markSent() {
+	atomic.StoreUint32(&ev.sent, 1)
+
+	o := ev.order
+	if o == nil {
+		return // not captured
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.changed != nil {
+		close(o.changed)
+		o.changed = nil
+	}
+}
+
+// Sent returns the captured events for which Msg or Send has been called, in the order
+// they were logged. As with All, each event is the level setting before its fields.
+//
+// Unlike All, Sent can be used safely while other goroutines are still logging, so it
+// suits Gomega's Eventually, e.g.
+//
+//	Eventually(tl.Sent).Should(matchers.HaveLogged(zerolog.InfoLevel, "done"))
+func (l *TestLogger) Sent() *TestLogEventList {
+	sent, _ := l.sent()
+	return sent
+}
+
+// sent also returns a channel that will be closed when another event is sent.
+func (l *TestLogger) sent() (*TestLogEventList, <-chan struct{}) {
+	l.order.mu.Lock()
+	defer l.order.mu.Unlock()
+
+	if l.order.changed == nil {
+		l.order.changed = make(chan struct{})
+	}
+	return l.All.Filter((*TestLogEvent).IsTerminated), l.order.changed
+}
+
+// WaitFor blocks until an event that satisfies the predicate has been sent by Msg or Send,
+// and returns it. If ctx is done first, the result is nil and the context's error.
+// As with TestLogEventList.First, the event is the first item after the level setting.
+//
+// Events that were already sent are also considered, so WaitFor can be called before
+// or after the event is logged.
+func (l *TestLogger) WaitFor(ctx context.Context, predicate func(*TestLogEvent) bool) (*TestLogEvent, error) {
+	for {
+		sent, changed := l.sent()
+		if found, exists := sent.Find(func(ev *TestLogEvent) bool { return predicate(ev.Next) }); exists {
+			return found.Next, nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Logged returns a function that reports whether an event that satisfies the predicate
+// has been sent. This suits Gomega's Eventually and testify's Eventually, e.g.
+//
+//	Eventually(tl.Logged(func(ev *TestLogEvent) bool { return ev.Message() == "done" })).Should(BeTrue())
+func (l *TestLogger) Logged(predicate func(*TestLogEvent) bool) func() bool {
+	return func() bool {
+		return l.Sent().Exists(func(ev *TestLogEvent) bool { return predicate(ev.Next) })
+	}
+}
@@ -0,0 +1,92 @@
+package testlogger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+func TestWaitFor(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := New(nil)
+	tl.Info().Msg("before")
+
+	go func() {
+		for i := 0; i < 5; i++ {
+			ev := tl.Warn().Int("i", i)
+			time.Sleep(time.Millisecond)
+			ev.Msg("working")
+		}
+		tl.Str("job", "j1").Info().Msg("done")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ev, err := tl.WaitFor(ctx, func(ev *TestLogEvent) bool { return ev.Message() == "done" })
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ev.String()).To(Equal("Str(job, j1).Msg(done)"))
+	g.Expect(tl.Sent().Len()).To(Equal(7))
+
+	ev, err = tl.WaitFor(ctx, func(ev *TestLogEvent) bool { return ev.Message() == "before" })
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ev.Level()).To(Equal(zerolog.InfoLevel))
+}
+
+func TestWaitFor_timeout(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := New(nil)
+	tl.Info().Str("a", "unsent")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	ev, err := tl.WaitFor(ctx, func(ev *TestLogEvent) bool { return ev.HasKey("a") })
+	g.Expect(ev).To(BeNil())
+	g.Expect(err).To(Equal(context.DeadlineExceeded))
+	g.Expect(tl.Sent().IsEmpty()).To(BeTrue())
+}
+
+func TestLogged(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := New(nil)
+
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		tl.Error().Int("code", 3).Msg("failed")
+	}()
+
+	g.Eventually(tl.Logged(func(ev *TestLogEvent) bool { return ev.Level() == zerolog.ErrorLevel })).Should(BeTrue())
+	g.Expect(tl.Logged(func(ev *TestLogEvent) bool { return ev.Message() == "other" })()).To(BeFalse())
+}
+
+func TestIsTerminated_concurrent(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tl := New(nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			tl.Info().Int("i", i).Msg("m")
+		}
+	}()
+
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		for _, ev := range tl.All.ToSlice() {
+			if ev.IsTerminated() {
+				g.Expect(ev.Message()).To(Equal("m"))
+			}
+		}
+	}
+	g.Expect(tl.All.Forall((*TestLogEvent).IsTerminated)).To(BeTrue())
+	g.Expect(tl.Sent().Len()).To(Equal(100))
+}
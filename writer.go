@@ -0,0 +1,91 @@
+package ech0
+
+import (
+	"bytes"
+	stdlog "log"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// SourceFieldName is the field that identifies the origin of events
+// produced by an EventWriter.
+var SourceFieldName = "source"
+
+// EventWriter is an io.Writer that turns each line of raw text written to it
+// into a log event. This allows plain-text output, such as echo's startup banner
+// or the standard library logger, to be interleaved safely with JSON log events.
+//
+// Blank lines are dropped. An incomplete final line is held back until it is
+// completed or Flush is called.
+type EventWriter struct {
+	mu     sync.Mutex
+	zl     Zero
+	level  zerolog.Level
+	source string
+	buf    []byte
+}
+
+// NewEventWriter returns a writer that logs each line at the given level. If source is not
+// blank, it is added to each event as the "source" field (see SourceFieldName).
+func NewEventWriter(z Zero, level zerolog.Level, source string) *EventWriter {
+	return &EventWriter{zl: z, level: level, source: source}
+}
+
+// NewStdLogger returns a standard library logger that writes through an EventWriter.
+// This is suitable for http.Server.ErrorLog, for example.
+// For the global standard logger, use log.SetOutput(NewEventWriter(...)) and log.SetFlags(0).
+func NewStdLogger(z Zero, level zerolog.Level, source string) *stdlog.Logger {
+	return stdlog.New(NewEventWriter(z, level, source), "", 0)
+}
+
+// Write satisfies io.Writer. It always consumes all of p.
+func (w *EventWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+
+	if len(w.buf) == 0 {
+		w.buf = nil // release the backing array
+	}
+	return len(p), nil
+}
+
+// Flush logs any incomplete final line.
+func (w *EventWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.emit(string(w.buf))
+	w.buf = nil
+	return nil
+}
+
+func (w *EventWriter) setZero(z Zero) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.zl = z
+}
+
+func (w *EventWriter) emit(line string) {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
+	ev := w.zl.WithLevel(w.level)
+	if w.source != "" {
+		ev = ev.Str(SourceFieldName, w.source)
+	}
+	ev.Msg(line)
+}
@@ -0,0 +1,63 @@
+package ech0
+
+import (
+	"fmt"
+	stdlog "log"
+	"strings"
+	"testing"
+
+	gommon "github.com/labstack/gommon/log"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+func TestEventWriter(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	w := NewEventWriter(Wrap(zerolog.New(buf)), zerolog.WarnLevel, "banner")
+
+	fmt.Fprint(w, "line one\n\n  \nline ")
+	g.Expect(buf.String()).To(Equal(`{"level":"warn","source":"banner","message":"line one"}` + "\n"))
+
+	fmt.Fprint(w, "two\r\nthree")
+	w.Flush()
+	g.Expect(buf.String()).To(Equal(`{"level":"warn","source":"banner","message":"line one"}` + "\n" +
+		`{"level":"warn","source":"banner","message":"line two"}` + "\n" +
+		`{"level":"warn","source":"banner","message":"three"}` + "\n"))
+}
+
+func TestNewStdLogger(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	sl := NewStdLogger(Wrap(zerolog.New(buf)), zerolog.ErrorLevel, "")
+
+	sl.Printf("http: TLS handshake error from %s", "1.2.3.4")
+
+	g.Expect(buf.String()).To(Equal(`{"level":"error","message":"http: TLS handshake error from 1.2.3.4"}` + "\n"))
+}
+
+func TestLog_SetOutputEvents(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	l := New(buf, "")
+	g.Expect(l.Output()).To(BeIdenticalTo(buf))
+
+	l.SetOutputEvents(zerolog.InfoLevel, "echo")
+	buf2 := &strings.Builder{}
+	l.SetOutput(buf2)
+	fmt.Fprintf(l.Output(), "⇨ http server started on %s\n", "[::]:1323")
+	g.Expect(buf.String()).To(BeEmpty())
+	g.Expect(buf2.String()).To(MatchRegexp(`^\{"level":"info","source":"echo","time":"[^"]+","message":"⇨ http server started on \[::\]:1323"\}` + "\n$"))
+
+	// events are subject to the logger level
+	buf2.Reset()
+	l.SetLevel(gommon.WARN)
+	fmt.Fprintln(l.Output(), "ignored")
+	g.Expect(buf2.String()).To(BeEmpty())
+
+	stdlog.New(l.Output(), "", 0).Print("also ignored")
+	g.Expect(buf2.String()).To(BeEmpty())
+
+	l.SetOutputEvents(zerolog.Disabled, "")
+	g.Expect(l.Output()).To(BeIdenticalTo(buf2))
+}
@@ -0,0 +1,413 @@
+package ech0
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ZeroContext mimics zerolog.Context. It is used to build a child logger with
+// additional context fields; obtain one from Zero.With and finish with Logger.
+type ZeroContext interface {
+	// Logger returns the logger with the context previously set.
+	Logger() Zero
+
+	// Str adds the field key with val as a string to the logger context.
+	Str(key, val string) ZeroContext
+	// Strs adds the field key with vals as a []string to the logger context.
+	Strs(key string, vals []string) ZeroContext
+	// Stringer adds the field key with val.String() (or null if val is nil) to the logger context.
+	Stringer(key string, val fmt.Stringer) ZeroContext
+	// Bytes adds the field key with val as a []byte to the logger context.
+	Bytes(key string, val []byte) ZeroContext
+	// Hex adds the field key with val as a hex string to the logger context.
+	Hex(key string, val []byte) ZeroContext
+	// RawJSON adds already encoded JSON to the logger context.
+	//
+	// No sanity check is performed on b; it must not contain carriage returns and
+	// be valid JSON.
+	RawJSON(key string, b []byte) ZeroContext
+	// AnErr adds the field key with serialized err to the logger context.
+	AnErr(key string, err error) ZeroContext
+	// Errs adds the field key with errs as an array of serialized errors to the
+	// logger context.
+	Errs(key string, errs []error) ZeroContext
+	// Err adds the field "error" with serialized err to the logger context.
+	Err(err error) ZeroContext
+	// Bool adds the field key with b as a bool to the logger context.
+	Bool(key string, b bool) ZeroContext
+	// Bools adds the field key with b as a []bool to the logger context.
+	Bools(key string, b []bool) ZeroContext
+	// Int adds the field key with i as a int to the logger context.
+	Int(key string, i int) ZeroContext
+	// Ints adds the field key with i as a []int to the logger context.
+	Ints(key string, i []int) ZeroContext
+	// Int8 adds the field key with i as a int8 to the logger context.
+	Int8(key string, i int8) ZeroContext
+	// Ints8 adds the field key with i as a []int8 to the logger context.
+	Ints8(key string, i []int8) ZeroContext
+	// Int16 adds the field key with i as a int16 to the logger context.
+	Int16(key string, i int16) ZeroContext
+	// Ints16 adds the field key with i as a []int16 to the logger context.
+	Ints16(key string, i []int16) ZeroContext
+	// Int32 adds the field key with i as a int32 to the logger context.
+	Int32(key string, i int32) ZeroContext
+	// Ints32 adds the field key with i as a []int32 to the logger context.
+	Ints32(key string, i []int32) ZeroContext
+	// Int64 adds the field key with i as a int64 to the logger context.
+	Int64(key string, i int64) ZeroContext
+	// Ints64 adds the field key with i as a []int64 to the logger context.
+	Ints64(key string, i []int64) ZeroContext
+	// Uint adds the field key with i as a uint to the logger context.
+	Uint(key string, i uint) ZeroContext
+	// Uints adds the field key with i as a []uint to the logger context.
+	Uints(key string, i []uint) ZeroContext
+	// Uint8 adds the field key with i as a uint8 to the logger context.
+	Uint8(key string, i uint8) ZeroContext
+	// Uints8 adds the field key with i as a []uint8 to the logger context.
+	Uints8(key string, i []uint8) ZeroContext
+	// Uint16 adds the field key with i as a uint16 to the logger context.
+	Uint16(key string, i uint16) ZeroContext
+	// Uints16 adds the field key with i as a []uint16 to the logger context.
+	Uints16(key string, i []uint16) ZeroContext
+	// Uint32 adds the field key with i as a uint32 to the logger context.
+	Uint32(key string, i uint32) ZeroContext
+	// Uints32 adds the field key with i as a []uint32 to the logger context.
+	Uints32(key string, i []uint32) ZeroContext
+	// Uint64 adds the field key with i as a uint64 to the logger context.
+	Uint64(key string, i uint64) ZeroContext
+	// Uints64 adds the field key with i as a []uint64 to the logger context.
+	Uints64(key string, i []uint64) ZeroContext
+	// Float32 adds the field key with f as a float32 to the logger context.
+	Float32(key string, f float32) ZeroContext
+	// Floats32 adds the field key with f as a []float32 to the logger context.
+	Floats32(key string, f []float32) ZeroContext
+	// Float64 adds the field key with f as a float64 to the logger context.
+	Float64(key string, f float64) ZeroContext
+	// Floats64 adds the field key with f as a []float64 to the logger context.
+	Floats64(key string, f []float64) ZeroContext
+	// Timestamp adds the current local time as UNIX timestamp to the logger context with the "time" key.
+	// To customize the key name, change zerolog.TimestampFieldName.
+	//
+	// NOTE: It won't dedupe the "time" key if the context has one already.
+	Timestamp() ZeroContext
+	// Time adds the field key with t formated as string using zerolog.TimeFieldFormat.
+	Time(key string, t time.Time) ZeroContext
+	// Times adds the field key with t formated as string using zerolog.TimeFieldFormat.
+	Times(key string, t []time.Time) ZeroContext
+	// Dur adds the fields key with d divided by unit and stored as a float.
+	Dur(key string, d time.Duration) ZeroContext
+	// Durs adds the fields key with d divided by unit and stored as a float.
+	Durs(key string, d []time.Duration) ZeroContext
+	// Interface adds the field key with i marshaled using reflection.
+	Interface(key string, i interface{}) ZeroContext
+	// Stack enables stack trace printing for the error passed to Err().
+	Stack() ZeroContext
+	// IPAddr adds IPv4 or IPv6 Address to the logger context.
+	IPAddr(key string, ip net.IP) ZeroContext
+	// IPPrefix adds IPv4 or IPv6 Prefix (address and mask) to the logger context.
+	IPPrefix(key string, pfx net.IPNet) ZeroContext
+	// MACAddr adds MAC address to the logger context.
+	MACAddr(key string, ha net.HardwareAddr) ZeroContext
+	// Fields is a helper function to use a map to set fields using type assertion.
+	Fields(fields map[string]interface{}) ZeroContext
+	// Dict adds the field key with the dict to the logger context.
+	Dict(key string, dict ZeroEvent) ZeroContext
+	// Caller adds the file:line of the caller with the zerolog.CallerFieldName key.
+	Caller() ZeroContext
+	// CallerWithSkipFrameCount adds the file:line of the caller with the zerolog.CallerFieldName key.
+	// The specified skipFrameCount int will override the global CallerSkipFrameCount for this context's respective logger.
+	// If set to -1 the global CallerSkipFrameCount will be used.
+	CallerWithSkipFrameCount(skipFrameCount int) ZeroContext
+}
+
+var _ ZeroContext = &zeroContext{}
+
+type zeroContext struct {
+	zc        zerolog.Context
+	stack     StackMarshaler // nil for the default, ErrorChain
+	withStack bool
+}
+
+// Logger returns the logger with the context previously set.
+func (c *zeroContext) Logger() Zero {
+	return &zeroFacade{zl: c.zc.Logger(), stack: c.stack, withStack: c.withStack}
+}
+
+// Dict adds the field key with the dict to the logger context.
+// Use Dict() to create the dictionary; see ZeroEvent.Dict.
+func (c *zeroContext) Dict(key string, dict ZeroEvent) ZeroContext {
+	switch d := unwrapDict(dict).(type) {
+	case *zeroEvent, disabledEvent, nil:
+		return c // not a replayable dictionary; see ZeroEvent.Dict
+	case ObjectMarshaler:
+		return c.with(c.Context().Object(key, objectMarshaler{obj: d, stack: c.stack}))
+	default:
+		return c.with(c.Context().Str(key, fmt.Sprint(dict)))
+	}
+}
+
+// Caller adds the file:line of the caller with the zerolog.CallerFieldName key.
+func (c *zeroContext) Caller() ZeroContext {
+	return c.CallerWithSkipFrameCount(-1)
+}
+
+// CallerWithSkipFrameCount adds the file:line of the caller with the zerolog.CallerFieldName key.
+// The specified skipFrameCount int will override the global CallerSkipFrameCount for this context's respective logger.
+// If set to -1 the global CallerSkipFrameCount will be used.
+func (c *zeroContext) CallerWithSkipFrameCount(skipFrameCount int) ZeroContext {
+	if skipFrameCount < 0 {
+		skipFrameCount = zerolog.CallerSkipFrameCount
+	}
+	// one more frame is needed to skip over the zeroEvent wrapper
+	return c.with(c.Context().CallerWithSkipFrameCount(skipFrameCount + 1))
+}
+
+// Str adds the field key with val as a string to the logger context.
+func (c *zeroContext) Str(key, val string) ZeroContext {
+	return c.with(c.Context().Str(key, val))
+}
+
+// Strs adds the field key with vals as a []string to the logger context.
+func (c *zeroContext) Strs(key string, vals []string) ZeroContext {
+	return c.with(c.Context().Strs(key, vals))
+}
+
+// Stringer adds the field key with val.String() (or null if val is nil) to the logger context.
+func (c *zeroContext) Stringer(key string, val fmt.Stringer) ZeroContext {
+	return c.with(c.Context().Stringer(key, val))
+}
+
+// Bytes adds the field key with val as a []byte to the logger context.
+func (c *zeroContext) Bytes(key string, val []byte) ZeroContext {
+	return c.with(c.Context().Bytes(key, val))
+}
+
+// Hex adds the field key with val as a hex string to the logger context.
+func (c *zeroContext) Hex(key string, val []byte) ZeroContext {
+	return c.with(c.Context().Hex(key, val))
+}
+
+// RawJSON adds already encoded JSON to the logger context.
+//
+// No sanity check is performed on b; it must not contain carriage returns and
+// be valid JSON.
+func (c *zeroContext) RawJSON(key string, b []byte) ZeroContext {
+	return c.with(c.Context().RawJSON(key, b))
+}
+
+// AnErr adds the field key with serialized err to the logger context.
+func (c *zeroContext) AnErr(key string, err error) ZeroContext {
+	return c.with(c.Context().AnErr(key, err))
+}
+
+// Errs adds the field key with errs as an array of serialized errors to the
+// logger context.
+func (c *zeroContext) Errs(key string, errs []error) ZeroContext {
+	return c.with(c.Context().Errs(key, errs))
+}
+
+// Err adds the field "error" with serialized err to the logger context.
+func (c *zeroContext) Err(err error) ZeroContext {
+	return c.with(c.Context().Err(err))
+}
+
+// Bool adds the field key with b as a bool to the logger context.
+func (c *zeroContext) Bool(key string, b bool) ZeroContext {
+	return c.with(c.Context().Bool(key, b))
+}
+
+// Bools adds the field key with b as a []bool to the logger context.
+func (c *zeroContext) Bools(key string, b []bool) ZeroContext {
+	return c.with(c.Context().Bools(key, b))
+}
+
+// Int adds the field key with i as a int to the logger context.
+func (c *zeroContext) Int(key string, i int) ZeroContext {
+	return c.with(c.Context().Int(key, i))
+}
+
+// Ints adds the field key with i as a []int to the logger context.
+func (c *zeroContext) Ints(key string, i []int) ZeroContext {
+	return c.with(c.Context().Ints(key, i))
+}
+
+// Int8 adds the field key with i as a int8 to the logger context.
+func (c *zeroContext) Int8(key string, i int8) ZeroContext {
+	return c.with(c.Context().Int8(key, i))
+}
+
+// Ints8 adds the field key with i as a []int8 to the logger context.
+func (c *zeroContext) Ints8(key string, i []int8) ZeroContext {
+	return c.with(c.Context().Ints8(key, i))
+}
+
+// Int16 adds the field key with i as a int16 to the logger context.
+func (c *zeroContext) Int16(key string, i int16) ZeroContext {
+	return c.with(c.Context().Int16(key, i))
+}
+
+// Ints16 adds the field key with i as a []int16 to the logger context.
+func (c *zeroContext) Ints16(key string, i []int16) ZeroContext {
+	return c.with(c.Context().Ints16(key, i))
+}
+
+// Int32 adds the field key with i as a int32 to the logger context.
+func (c *zeroContext) Int32(key string, i int32) ZeroContext {
+	return c.with(c.Context().Int32(key, i))
+}
+
+// Ints32 adds the field key with i as a []int32 to the logger context.
+func (c *zeroContext) Ints32(key string, i []int32) ZeroContext {
+	return c.with(c.Context().Ints32(key, i))
+}
+
+// Int64 adds the field key with i as a int64 to the logger context.
+func (c *zeroContext) Int64(key string, i int64) ZeroContext {
+	return c.with(c.Context().Int64(key, i))
+}
+
+// Ints64 adds the field key with i as a []int64 to the logger context.
+func (c *zeroContext) Ints64(key string, i []int64) ZeroContext {
+	return c.with(c.Context().Ints64(key, i))
+}
+
+// Uint adds the field key with i as a uint to the logger context.
+func (c *zeroContext) Uint(key string, i uint) ZeroContext {
+	return c.with(c.Context().Uint(key, i))
+}
+
+// Uints adds the field key with i as a []uint to the logger context.
+func (c *zeroContext) Uints(key string, i []uint) ZeroContext {
+	return c.with(c.Context().Uints(key, i))
+}
+
+// Uint8 adds the field key with i as a uint8 to the logger context.
+func (c *zeroContext) Uint8(key string, i uint8) ZeroContext {
+	return c.with(c.Context().Uint8(key, i))
+}
+
+// Uints8 adds the field key with i as a []uint8 to the logger context.
+func (c *zeroContext) Uints8(key string, i []uint8) ZeroContext {
+	return c.with(c.Context().Uints8(key, i))
+}
+
+// Uint16 adds the field key with i as a uint16 to the logger context.
+func (c *zeroContext) Uint16(key string, i uint16) ZeroContext {
+	return c.with(c.Context().Uint16(key, i))
+}
+
+// Uints16 adds the field key with i as a []uint16 to the logger context.
+func (c *zeroContext) Uints16(key string, i []uint16) ZeroContext {
+	return c.with(c.Context().Uints16(key, i))
+}
+
+// Uint32 adds the field key with i as a uint32 to the logger context.
+func (c *zeroContext) Uint32(key string, i uint32) ZeroContext {
+	return c.with(c.Context().Uint32(key, i))
+}
+
+// Uints32 adds the field key with i as a []uint32 to the logger context.
+func (c *zeroContext) Uints32(key string, i []uint32) ZeroContext {
+	return c.with(c.Context().Uints32(key, i))
+}
+
+// Uint64 adds the field key with i as a uint64 to the logger context.
+func (c *zeroContext) Uint64(key string, i uint64) ZeroContext {
+	return c.with(c.Context().Uint64(key, i))
+}
+
+// Uints64 adds the field key with i as a []uint64 to the logger context.
+func (c *zeroContext) Uints64(key string, i []uint64) ZeroContext {
+	return c.with(c.Context().Uints64(key, i))
+}
+
+// Float32 adds the field key with f as a float32 to the logger context.
+func (c *zeroContext) Float32(key string, f float32) ZeroContext {
+	return c.with(c.Context().Float32(key, f))
+}
+
+// Floats32 adds the field key with f as a []float32 to the logger context.
+func (c *zeroContext) Floats32(key string, f []float32) ZeroContext {
+	return c.with(c.Context().Floats32(key, f))
+}
+
+// Float64 adds the field key with f as a float64 to the logger context.
+func (c *zeroContext) Float64(key string, f float64) ZeroContext {
+	return c.with(c.Context().Float64(key, f))
+}
+
+// Floats64 adds the field key with f as a []float64 to the logger context.
+func (c *zeroContext) Floats64(key string, f []float64) ZeroContext {
+	return c.with(c.Context().Floats64(key, f))
+}
+
+// Timestamp adds the current local time as UNIX timestamp to the logger context with the "time" key.
+// To customize the key name, change zerolog.TimestampFieldName.
+//
+// NOTE: It won't dedupe the "time" key if the context has one already.
+func (c *zeroContext) Timestamp() ZeroContext {
+	return c.with(c.Context().Timestamp())
+}
+
+// Time adds the field key with t formated as string using zerolog.TimeFieldFormat.
+func (c *zeroContext) Time(key string, t time.Time) ZeroContext {
+	return c.with(c.Context().Time(key, t))
+}
+
+// Times adds the field key with t formated as string using zerolog.TimeFieldFormat.
+func (c *zeroContext) Times(key string, t []time.Time) ZeroContext {
+	return c.with(c.Context().Times(key, t))
+}
+
+// Dur adds the fields key with d divided by unit and stored as a float.
+func (c *zeroContext) Dur(key string, d time.Duration) ZeroContext {
+	return c.with(c.Context().Dur(key, d))
+}
+
+// Durs adds the fields key with d divided by unit and stored as a float.
+func (c *zeroContext) Durs(key string, d []time.Duration) ZeroContext {
+	return c.with(c.Context().Durs(key, d))
+}
+
+// Interface adds the field key with i marshaled using reflection.
+func (c *zeroContext) Interface(key string, i interface{}) ZeroContext {
+	return c.with(c.Context().Interface(key, i))
+}
+
+// Stack enables stack trace printing for the error passed to Err() in every event
+// of the child logger, using the logger's StackMarshaler (see Zero.ErrorStack).
+func (c *zeroContext) Stack() ZeroContext {
+	return &zeroContext{zc: c.zc, stack: c.stack, withStack: true}
+}
+
+// IPAddr adds IPv4 or IPv6 Address to the logger context.
+func (c *zeroContext) IPAddr(key string, ip net.IP) ZeroContext {
+	return c.with(c.Context().IPAddr(key, ip))
+}
+
+// IPPrefix adds IPv4 or IPv6 Prefix (address and mask) to the logger context.
+func (c *zeroContext) IPPrefix(key string, pfx net.IPNet) ZeroContext {
+	return c.with(c.Context().IPPrefix(key, pfx))
+}
+
+// MACAddr adds MAC address to the logger context.
+func (c *zeroContext) MACAddr(key string, ha net.HardwareAddr) ZeroContext {
+	return c.with(c.Context().MACAddr(key, ha))
+}
+
+// Fields is a helper function to use a map to set fields using type assertion.
+func (c *zeroContext) Fields(fields map[string]interface{}) ZeroContext {
+	return c.with(c.Context().Fields(fields))
+}
+
+// Context unwraps the actual context.
+func (c *zeroContext) Context() zerolog.Context {
+	return c.zc
+}
+
+func (c *zeroContext) with(zc zerolog.Context) ZeroContext {
+	return &zeroContext{zc: zc, stack: c.stack, withStack: c.withStack}
+}
@@ -0,0 +1,62 @@
+package ech0
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+func TestWith(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	z := Wrap(zerolog.New(buf))
+
+	child := z.With().
+		Str("s", "v").
+		Strs("ss", []string{"a", "b"}).
+		Bool("b", true).
+		Int8("i8", -8).
+		Ints16("i16", []int16{16}).
+		Uint32("u32", 32).
+		Uints64("u64", []uint64{64}).
+		Float32("f32", 1.5).
+		Floats64("f64", []float64{2.5}).
+		Time("t", t1).
+		Durs("d", []time.Duration{time.Second}).
+		AnErr("e", e1).
+		Errs("es", []error{e1, e2}).
+		IPAddr("ip", net.IPv4(10, 0, 0, 1)).
+		Interface("x", map[string]int{"y": 1}).
+		Dict("dict", Dict().Int("k", 1)).
+		Fields(map[string]interface{}{"m": "n"}).
+		Logger()
+
+	child.Info().Send()
+	g.Expect(buf.String()).To(Equal(`{"level":"info","s":"v","ss":["a","b"],"b":true,"i8":-8,"i16":[16],"u32":32,"u64":[64],` +
+		`"f32":1.5,"f64":[2.5],"t":"2020-12-01T13:14:15Z","d":[1000],"e":"x1","es":["x1","x2"],"ip":"10.0.0.1",` +
+		`"x":{"y":1},"dict":{"k":1},"m":"n"` + newline))
+
+	// the parent is not altered
+	buf.Reset()
+	z.Info().Send()
+	g.Expect(buf.String()).To(Equal(`{"level":"info"` + newline))
+
+	g.Expect(z.Bool("b", false).With().Str("c", "d").Logger()).NotTo(BeNil())
+}
+
+func TestWith_Caller(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	z := Wrap(zerolog.New(buf)).With().Caller().Logger()
+
+	z.Info().Msg("a")
+	g.Expect(buf.String()).To(MatchRegexp(`"caller":"[^"]*zcontext_test.go:\d+"`))
+
+	buf.Reset()
+	z.Warn().Msgf("%s", "b")
+	g.Expect(buf.String()).To(MatchRegexp(`"caller":"[^"]*zcontext_test.go:\d+"`))
+}
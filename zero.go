@@ -35,13 +35,20 @@ type Zero interface {
 
 	// Output duplicates the current logger and sets w as its output.
 	Output(w io.Writer) Zero
+	// ErrorStack creates a child logger that uses m to marshal the errors passed to
+	// ZeroEvent.Err after ZeroEvent.Stack has been called.
+	ErrorStack(m StackMarshaler) Zero
 	// Level creates a child logger with the minimum accepted level set to level.
 	Level(lvl zerolog.Level) Zero
 
+	// With creates a child logger builder, to which any fields can be added.
+	With() ZeroContext
 	// Str creates a child logger with the field key and with val as a string to the logger context.
 	Str(key, val string) Zero
 	// Int creates a child logger with the field key and with val as an int to the logger context.
 	Int(key string, val int) Zero
+	// Bool creates a child logger with the field key and with val as a bool to the logger context.
+	Bool(key string, val bool) Zero
 	// RawJSON creates a child logger with the field key with val as already encoded JSON to context.
 	//
 	// No sanity check is performed on b; it must not contain carriage returns and be valid JSON.
@@ -55,11 +62,32 @@ type Zero interface {
 
 var _ Zero = &zeroFacade{}
 
-type zeroFacade zerolog.Logger
+type zeroFacade struct {
+	zl        zerolog.Logger
+	stack     StackMarshaler // nil for the default, ErrorChain
+	withStack bool           // set by ZeroContext.Stack: every event behaves as if Stack was called
+}
 
-// Wrap wraps an existing logger.
+// Wrap wraps an existing logger. Stack traces are marshaled by ErrorChain;
+// use ErrorStack to change this.
 func Wrap(z zerolog.Logger) Zero {
-	return (*zeroFacade)(&z)
+	return &zeroFacade{zl: z}
+}
+
+func (z *zeroFacade) wrap(zl zerolog.Logger) Zero {
+	return &zeroFacade{zl: zl, stack: z.stack, withStack: z.withStack}
+}
+
+// event wraps e, which is nil if the event is not enabled. Only loggers whose stack
+// settings differ from the defaults need a stackEvent; otherwise, no allocation is needed.
+func (z *zeroFacade) event(e *zerolog.Event) ZeroEvent {
+	if e == nil {
+		return disabledEvent{}
+	}
+	if z.stack == nil && !z.withStack {
+		return (*zeroEvent)(e)
+	}
+	return &stackEvent{e: e, stack: z.stack, withStack: z.withStack}
 }
 
 // Log starts a new message with no level. Setting GlobalLevel to Disabled
@@ -67,35 +95,35 @@ func Wrap(z zerolog.Logger) Zero {
 //
 // You must call Msg on the returned event in order to send the event.
 func (z *zeroFacade) Log() ZeroEvent {
-	return (*zeroEvent)(z.Zero().Log())
+	return z.event(z.zl.Log())
 }
 
 // Debug starts a new message with debug level.
 //
 // You must call Msg on the returned event in order to send the event.
 func (z *zeroFacade) Debug() ZeroEvent {
-	return (*zeroEvent)(z.Zero().Debug())
+	return z.event(z.zl.Debug())
 }
 
 // Info starts a new message with info level.
 //
 // You must call Msg on the returned event in order to send the event.
 func (z *zeroFacade) Info() ZeroEvent {
-	return (*zeroEvent)(z.Zero().Info())
+	return z.event(z.zl.Info())
 }
 
 // Warn starts a new message with warn level.
 //
 // You must call Msg on the returned event in order to send the event.
 func (z *zeroFacade) Warn() ZeroEvent {
-	return (*zeroEvent)(z.Zero().Warn())
+	return z.event(z.zl.Warn())
 }
 
 // Error starts a new message with error level.
 //
 // You must call Msg on the returned event in order to send the event.
 func (z *zeroFacade) Error() ZeroEvent {
-	return (*zeroEvent)(z.Zero().Error())
+	return z.event(z.zl.Error())
 }
 
 // Err starts a new message with error level with err as a field if not nil or
@@ -103,7 +131,10 @@ func (z *zeroFacade) Error() ZeroEvent {
 //
 // You must call Msg on the returned event in order to send the event.
 func (z *zeroFacade) Err(err error) ZeroEvent {
-	return (*zeroEvent)(z.Zero().Err(err))
+	if err != nil {
+		return z.Error().Err(err) // applies the logger's StackMarshaler
+	}
+	return z.Info()
 }
 
 // Fatal starts a new message with fatal level. The os.Exit(1) function
@@ -111,7 +142,7 @@ func (z *zeroFacade) Err(err error) ZeroEvent {
 //
 // You must call Msg on the returned event in order to send the event.
 func (z *zeroFacade) Fatal() ZeroEvent {
-	return (*zeroEvent)(z.Zero().Fatal())
+	return z.event(z.zl.Fatal())
 }
 
 // Panic starts a new message with panic level. The panic() function
@@ -119,7 +150,7 @@ func (z *zeroFacade) Fatal() ZeroEvent {
 //
 // You must call Msg on the returned event in order to send the event.
 func (z *zeroFacade) Panic() ZeroEvent {
-	return (*zeroEvent)(z.Zero().Panic())
+	return z.event(z.zl.Panic())
 }
 
 // WithLevel starts a new message with level. Unlike Fatal and Panic
@@ -128,39 +159,45 @@ func (z *zeroFacade) Panic() ZeroEvent {
 //
 // You must call Msg on the returned event in order to send the event.
 func (z *zeroFacade) WithLevel(level zerolog.Level) ZeroEvent {
-	return (*zeroEvent)(z.Zero().WithLevel(level))
+	return z.event(z.zl.WithLevel(level))
 }
 
 // Output duplicates the current logger and sets w as its output.
 func (z *zeroFacade) Output(w io.Writer) Zero {
-	return Wrap(z.Zero().Output(w))
+	return z.wrap(z.zl.Output(w))
 }
 
 // Level creates a child logger with the minimum accepted level set to level.
 func (z *zeroFacade) Level(lvl zerolog.Level) Zero {
-	return Wrap(z.Zero().Level(lvl))
+	return z.wrap(z.zl.Level(lvl))
+}
+
+// With creates a child logger builder, to which any fields can be added.
+// Call Logger on the result to obtain the child logger.
+func (z *zeroFacade) With() ZeroContext {
+	return &zeroContext{zc: z.zl.With(), stack: z.stack, withStack: z.withStack}
 }
 
 // Str creates a child logger with the field key and with val as a string to the logger context.
 func (z *zeroFacade) Str(key, val string) Zero {
-	return Wrap(z.Zero().With().Str(key, val).Logger())
+	return z.wrap(z.zl.With().Str(key, val).Logger())
 }
 
 // Int creates a child logger with the field key and with val as an int to the logger context.
 func (z *zeroFacade) Int(key string, val int) Zero {
-	return Wrap(z.Zero().With().Int(key, val).Logger())
+	return z.wrap(z.zl.With().Int(key, val).Logger())
 }
 
 // Bool creates a child logger with the field key and with val as a bool to the logger context.
 func (z *zeroFacade) Bool(key string, val bool) Zero {
-	return Wrap(z.Zero().With().Bool(key, val).Logger())
+	return z.wrap(z.zl.With().Bool(key, val).Logger())
 }
 
 // RawJSON creates a child logger with the field key with val as already encoded JSON to context.
 //
 // No sanity check is performed on b; it must not contain carriage returns and be valid JSON.
 func (z *zeroFacade) RawJSON(key string, val []byte) Zero {
-	return Wrap(z.Zero().With().RawJSON(key, val).Logger())
+	return z.wrap(z.zl.With().RawJSON(key, val).Logger())
 }
 
 // Timestamp creates a child logger and adds the current local time as UNIX timestamp to the
@@ -168,10 +205,19 @@ func (z *zeroFacade) RawJSON(key string, val []byte) Zero {
 //
 // NOTE: It won't dedupe the "time" key if the internal context has one already.
 func (z *zeroFacade) Timestamp() Zero {
-	return Wrap(z.Zero().With().Timestamp().Logger())
+	return z.wrap(z.zl.With().Timestamp().Logger())
+}
+
+// ErrorStack creates a child logger that uses m to marshal the errors passed to
+// ZeroEvent.Err after ZeroEvent.Stack has been called. If m is nil, no stack is logged.
+func (z *zeroFacade) ErrorStack(m StackMarshaler) Zero {
+	if m == nil {
+		m = noStack
+	}
+	return &zeroFacade{zl: z.zl, stack: m, withStack: z.withStack}
 }
 
 // Zero unwraps the actual logger.
 func (z *zeroFacade) Zero() *zerolog.Logger {
-	return (*zerolog.Logger)(z)
+	return &z.zl
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	. "github.com/onsi/gomega"
 	"github.com/rs/zerolog"
+	"net"
 	"strings"
 	"testing"
 	"time"
@@ -143,3 +144,62 @@ const newline = "}\n"
 var e1 = errors.New("x1")
 var e2 = errors.New("x2")
 var t1 = time.Date(2020, 12, 1, 13, 14, 15, 0, time.UTC)
+
+func TestNumericAndNetworkFields(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	z := Wrap(zerolog.New(buf))
+
+	_, pfx, _ := net.ParseCIDR("10.1.0.0/16")
+	mac, _ := net.ParseMAC("00:00:5e:00:53:01")
+
+	z.Info().
+		Float32("f32", 1.5).
+		Float64("f64", 2.25).
+		Floats32("fs32", []float32{1}).
+		Floats64("fs64", []float64{2, 3}).
+		Int8("i8", -8).
+		Int16("i16", 16).
+		Int32("i32", 32).
+		Ints64("is64", []int64{64}).
+		Uint8("u8", 8).
+		Uint16("u16", 16).
+		Uint32("u32", 32).
+		Uints8("us8", []uint8{8}).
+		IPAddr("ip", net.IPv6loopback).
+		IPPrefix("pfx", *pfx).
+		MACAddr("mac", mac).
+		Durs("ds", []time.Duration{time.Second, time.Millisecond}).
+		Times("ts", []time.Time{t1}).
+		TimeDiff("td", t1.Add(time.Second), t1).
+		Errs("es", []error{e1, e2}).
+		Send()
+
+	g.Expect(buf.String()).To(Equal(`{"level":"info","f32":1.5,"f64":2.25,"fs32":[1],"fs64":[2,3],"i8":-8,"i16":16,"i32":32,"is64":[64],` +
+		`"u8":8,"u16":16,"u32":32,"us8":[8],"ip":"::1","pfx":"10.1.0.0/16","mac":"00:00:5e:00:53:01",` +
+		`"ds":[1000,1],"ts":["2020-12-01T13:14:15Z"],"td":1000,"es":["x1","x2"]` + newline))
+}
+
+func TestEnabledDiscardFunc(t *testing.T) {
+	g := NewGomegaWithT(t)
+	buf := &strings.Builder{}
+	z := Wrap(zerolog.New(buf).Level(zerolog.InfoLevel))
+
+	calls := 0
+	expensive := func(e ZeroEvent) {
+		calls++
+		e.Int("n", 42)
+	}
+
+	g.Expect(z.Debug().Enabled()).To(BeFalse())
+	z.Debug().Func(expensive).Msg("hidden")
+	g.Expect(calls).To(Equal(0))
+
+	g.Expect(z.Info().Enabled()).To(BeTrue())
+	z.Info().Func(expensive).Msg("shown")
+	g.Expect(calls).To(Equal(1))
+
+	z.Warn().Discard().Int("a", 1).Msg("dropped")
+
+	g.Expect(buf.String()).To(Equal(`{"level":"info","n":42,"message":"shown"}` + "\n"))
+}
@@ -3,6 +3,7 @@ package ech0
 import (
 	"fmt"
 	"github.com/rs/zerolog"
+	"net"
 	"time"
 )
 
@@ -11,14 +12,20 @@ type ZeroEvent interface {
 	Send()
 	Msg(string)
 	Msgf(format string, v ...interface{})
-	//Enabled() bool
-	//Discard() ZeroEvent
+	Enabled() bool
+	Discard() ZeroEvent
+	Func(f func(e ZeroEvent)) ZeroEvent
+	Stack() ZeroEvent
+	Caller(skip ...int) ZeroEvent
 
 	AnErr(key string, val error) ZeroEvent
 	Bool(key string, val bool) ZeroEvent
 	Bools(key string, b []bool) ZeroEvent
 	Bytes(key string, val []byte) ZeroEvent
 	Dict(key string, dict ZeroEvent) ZeroEvent
+	Object(key string, obj ObjectMarshaler) ZeroEvent
+	EmbedObject(obj ObjectMarshaler) ZeroEvent
+	Array(key string, arr ArrayMarshaler) ZeroEvent
 	Dur(key string, val time.Duration) ZeroEvent
 	Err(err error) ZeroEvent
 	Hex(key string, val []byte) ZeroEvent
@@ -34,10 +41,38 @@ type ZeroEvent interface {
 	Uint(key string, val uint) ZeroEvent
 	Uints(key string, val []uint) ZeroEvent
 	Uint64(key string, val uint64) ZeroEvent
+
+	Errs(key string, errs []error) ZeroEvent
+	Float32(key string, val float32) ZeroEvent
+	Floats32(key string, val []float32) ZeroEvent
+	Float64(key string, val float64) ZeroEvent
+	Floats64(key string, val []float64) ZeroEvent
+	Int8(key string, val int8) ZeroEvent
+	Int16(key string, val int16) ZeroEvent
+	Int32(key string, val int32) ZeroEvent
+	Ints8(key string, val []int8) ZeroEvent
+	Ints16(key string, val []int16) ZeroEvent
+	Ints32(key string, val []int32) ZeroEvent
+	Ints64(key string, val []int64) ZeroEvent
+	Uint8(key string, val uint8) ZeroEvent
+	Uint16(key string, val uint16) ZeroEvent
+	Uint32(key string, val uint32) ZeroEvent
+	Uints8(key string, val []uint8) ZeroEvent
+	Uints16(key string, val []uint16) ZeroEvent
+	Uints32(key string, val []uint32) ZeroEvent
+	Uints64(key string, val []uint64) ZeroEvent
+	Times(key string, val []time.Time) ZeroEvent
+	Durs(key string, val []time.Duration) ZeroEvent
+	TimeDiff(key string, t time.Time, start time.Time) ZeroEvent
+	IPAddr(key string, ip net.IP) ZeroEvent
+	IPPrefix(key string, pfx net.IPNet) ZeroEvent
+	MACAddr(key string, ha net.HardwareAddr) ZeroEvent
 }
 
 var _ ZeroEvent = &zeroEvent{}
 
+// zeroEvent is a zerolog.Event with the logger's default stack settings, so that
+// converting between them costs nothing; see stackEvent.
 type zeroEvent zerolog.Event
 
 // Send is equivalent to calling Msg("").
@@ -75,6 +110,15 @@ func (ze *zeroEvent) Discard() ZeroEvent {
 	return (*zeroEvent)(ev)
 }
 
+// Func allows an anonymous func to run only if the event is enabled. This
+// avoids computing fields that would not be logged.
+func (ze *zeroEvent) Func(f func(e ZeroEvent)) ZeroEvent {
+	if ze.Enabled() {
+		f(ze)
+	}
+	return ze
+}
+
 //-------------------------------------------------------------------------------------------------
 
 // AnErr adds the field key with serialized err to the ZeroEvent context.
@@ -106,20 +150,46 @@ func (ze *zeroEvent) Bytes(key string, val []byte) ZeroEvent {
 }
 
 // Dict adds the field key with a dict to the event context.
-// Use zerolog.Dict() to create the dictionary.
+// Use Dict() to create the dictionary. Any ZeroEvent that implements ObjectMarshaler
+// can be used as the dictionary, such as a testlogger event, because its fields can be
+// replayed.
+//
+// Events created by a logger from Wrap cannot be used: zerolog would take ownership of
+// them, and they carry the logger's own fields. They are ignored, as are disabled
+// dictionaries and Tee events with no replayable branch. Other values are added using
+// fmt.Sprint.
 func (ze *zeroEvent) Dict(key string, dict ZeroEvent) ZeroEvent {
-	if ze == nil {
-		return ze
+	switch d := unwrapDict(dict).(type) {
+	case *zeroEvent, *stackEvent, disabledEvent, nil:
+		// not a replayable dictionary
+	case ObjectMarshaler:
+		ev := (*zerolog.Event)(ze).Object(key, objectMarshaler{obj: d})
+		return (*zeroEvent)(ev)
+	default:
+		ev := (*zerolog.Event)(ze).Str(key, fmt.Sprint(dict))
+		return (*zeroEvent)(ev)
 	}
-	ev := (*zerolog.Event)(ze).Dict(key, (*zerolog.Event)(dict.(*zeroEvent)))
+	return ze
+}
+
+// Object marshals an object that implements the ObjectMarshaler interface.
+func (ze *zeroEvent) Object(key string, obj ObjectMarshaler) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Object(key, objectMarshaler{obj: obj})
+	return (*zeroEvent)(ev)
+}
+
+// EmbedObject marshals an object that implements the ObjectMarshaler interface,
+// adding its fields directly to the ZeroEvent context.
+func (ze *zeroEvent) EmbedObject(obj ObjectMarshaler) ZeroEvent {
+	ev := (*zerolog.Event)(ze).EmbedObject(objectMarshaler{obj: obj})
 	return (*zeroEvent)(ev)
 }
 
-// Dict creates an Event to be used with the ZeroEvent.Dict method.
-// Call usual field methods like Str, Int etc to add fields to this
-// event and give it as argument the *Event.Dict method.
-func Dict() ZeroEvent {
-	ev := zerolog.Dict()
+// Array adds the field key with an array to the ZeroEvent context.
+// Use Arr() to create the array or pass a type that
+// implements the ArrayMarshaler interface.
+func (ze *zeroEvent) Array(key string, arr ArrayMarshaler) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Array(key, arrayMarshaler{arr})
 	return (*zeroEvent)(ev)
 }
 
@@ -136,11 +206,28 @@ func (ze *zeroEvent) Dur(key string, val time.Duration) ZeroEvent {
 //
 // To customize the key name, change zerolog.ErrorFieldName.
 //
-// If Stack() has been called before and zerolog.ErrorStackMarshaler is defined,
-// the err is passed to ErrorStackMarshaler and the result is appended to the
-// zerolog.ErrorStackFieldName.
+// If Stack() has been called before, the err is passed to the logger's StackMarshaler
+// (see Zero.ErrorStack) and the result is appended to the zerolog.ErrorStackFieldName.
+// The global zerolog.ErrorStackMarshaler is not used.
 func (ze *zeroEvent) Err(err error) ZeroEvent {
-	ev := (*zerolog.Event)(ze).Err(err)
+	ev := (*zerolog.Event)(ze).Err(err) // Stack has not been called; see stackEvent
+	return (*zeroEvent)(ev)
+}
+
+// Stack enables stack trace printing for the error passed to Err().
+func (ze *zeroEvent) Stack() ZeroEvent {
+	return &stackEvent{e: (*zerolog.Event)(ze), withStack: true}
+}
+
+// Caller adds the file:line of the caller with the zerolog.CallerFieldName key.
+// The argument skip is the number of additional stack frames to ascend;
+// zerolog.CallerSkipFrameCount is always applied.
+func (ze *zeroEvent) Caller(skip ...int) ZeroEvent {
+	sk := 1 // skip this method
+	if len(skip) > 0 {
+		sk += skip[0]
+	}
+	ev := (*zerolog.Event)(ze).Caller(sk)
 	return (*zeroEvent)(ev)
 }
 
@@ -225,3 +312,157 @@ func (ze *zeroEvent) Uint64(key string, val uint64) ZeroEvent {
 	ev := (*zerolog.Event)(ze).Uint64(key, val)
 	return (*zeroEvent)(ev)
 }
+
+//-------------------------------------------------------------------------------------------------
+
+// Errs adds the field key with errs as an array of serialized errors to the ZeroEvent context.
+func (ze *zeroEvent) Errs(key string, errs []error) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Errs(key, errs)
+	return (*zeroEvent)(ev)
+}
+
+// Float32 adds the field key with val as a float32 to the ZeroEvent context.
+func (ze *zeroEvent) Float32(key string, val float32) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Float32(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Floats32 adds the field key with val as a []float32 to the ZeroEvent context.
+func (ze *zeroEvent) Floats32(key string, val []float32) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Floats32(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Float64 adds the field key with val as a float64 to the ZeroEvent context.
+func (ze *zeroEvent) Float64(key string, val float64) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Float64(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Floats64 adds the field key with val as a []float64 to the ZeroEvent context.
+func (ze *zeroEvent) Floats64(key string, val []float64) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Floats64(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Int8 adds the field key with val as a int8 to the ZeroEvent context.
+func (ze *zeroEvent) Int8(key string, val int8) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Int8(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Int16 adds the field key with val as a int16 to the ZeroEvent context.
+func (ze *zeroEvent) Int16(key string, val int16) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Int16(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Int32 adds the field key with val as a int32 to the ZeroEvent context.
+func (ze *zeroEvent) Int32(key string, val int32) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Int32(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Ints8 adds the field key with val as a []int8 to the ZeroEvent context.
+func (ze *zeroEvent) Ints8(key string, val []int8) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Ints8(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Ints16 adds the field key with val as a []int16 to the ZeroEvent context.
+func (ze *zeroEvent) Ints16(key string, val []int16) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Ints16(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Ints32 adds the field key with val as a []int32 to the ZeroEvent context.
+func (ze *zeroEvent) Ints32(key string, val []int32) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Ints32(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Ints64 adds the field key with val as a []int64 to the ZeroEvent context.
+func (ze *zeroEvent) Ints64(key string, val []int64) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Ints64(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Uint8 adds the field key with val as a uint8 to the ZeroEvent context.
+func (ze *zeroEvent) Uint8(key string, val uint8) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Uint8(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Uint16 adds the field key with val as a uint16 to the ZeroEvent context.
+func (ze *zeroEvent) Uint16(key string, val uint16) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Uint16(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Uint32 adds the field key with val as a uint32 to the ZeroEvent context.
+func (ze *zeroEvent) Uint32(key string, val uint32) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Uint32(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Uints8 adds the field key with val as a []uint8 to the ZeroEvent context.
+func (ze *zeroEvent) Uints8(key string, val []uint8) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Uints8(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Uints16 adds the field key with val as a []uint16 to the ZeroEvent context.
+func (ze *zeroEvent) Uints16(key string, val []uint16) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Uints16(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Uints32 adds the field key with val as a []uint32 to the ZeroEvent context.
+func (ze *zeroEvent) Uints32(key string, val []uint32) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Uints32(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Uints64 adds the field key with val as a []uint64 to the ZeroEvent context.
+func (ze *zeroEvent) Uints64(key string, val []uint64) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Uints64(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Times adds the field key with val formated as string using zerolog.TimeFieldFormat.
+func (ze *zeroEvent) Times(key string, val []time.Time) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Times(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// Durs adds the field key with val stored as zerolog.DurationFieldUnit.
+func (ze *zeroEvent) Durs(key string, val []time.Duration) ZeroEvent {
+	ev := (*zerolog.Event)(ze).Durs(key, val)
+	return (*zeroEvent)(ev)
+}
+
+// TimeDiff adds the field key with positive duration between time t and start.
+// If time t is not greater than start, duration will be 0.
+// Duration format follows the same principle as Dur().
+func (ze *zeroEvent) TimeDiff(key string, t time.Time, start time.Time) ZeroEvent {
+	ev := (*zerolog.Event)(ze).TimeDiff(key, t, start)
+	return (*zeroEvent)(ev)
+}
+
+// IPAddr adds IPv4 or IPv6 Address to the ZeroEvent context.
+func (ze *zeroEvent) IPAddr(key string, ip net.IP) ZeroEvent {
+	ev := (*zerolog.Event)(ze).IPAddr(key, ip)
+	return (*zeroEvent)(ev)
+}
+
+// IPPrefix adds IPv4 or IPv6 Prefix (address and mask) to the ZeroEvent context.
+func (ze *zeroEvent) IPPrefix(key string, pfx net.IPNet) ZeroEvent {
+	ev := (*zerolog.Event)(ze).IPPrefix(key, pfx)
+	return (*zeroEvent)(ev)
+}
+
+// MACAddr adds MAC address to the ZeroEvent context.
+func (ze *zeroEvent) MACAddr(key string, ha net.HardwareAddr) ZeroEvent {
+	ev := (*zerolog.Event)(ze).MACAddr(key, ha)
+	return (*zeroEvent)(ev)
+}